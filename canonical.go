@@ -0,0 +1,167 @@
+package jwk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// writeJSON write value as JSON with newline, like `json.Encoder`
+func writeJSON(dst io.Writer, v interface{}, canonical bool, indent string, escapeHTML bool) error {
+	if !canonical {
+		enc := json.NewEncoder(dst)
+		enc.SetEscapeHTML(escapeHTML)
+		enc.SetIndent("", indent)
+		return enc.Encode(v)
+	}
+	bts, err := canonicalJSON(v)
+	if err != nil {
+		return err
+	}
+	buf := bytes.NewBuffer(nil)
+	if len(indent) > 0 {
+		if err := json.Indent(buf, bts, "", indent); err != nil {
+			return err
+		}
+	} else {
+		buf.Write(bts)
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(dst)
+	return err
+}
+
+// canonicalJSON serialize value with JSON Canonicalization Scheme
+// https://www.rfc-editor.org/rfc/rfc8785
+func canonicalJSON(v interface{}) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	// normalize into generic JSON value, numbers are kept as it is
+	var generic interface{}
+	dec := json.NewDecoder(buf)
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	res := bytes.NewBuffer(nil)
+	if err := writeCanonical(res, generic); err != nil {
+		return nil, err
+	}
+	return res.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("can't canonicalize number %s", v)
+		}
+		buf.WriteString(canonicalNumber(f))
+	case string:
+		writeCanonicalString(buf, v)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		// https://www.rfc-editor.org/rfc/rfc8785#section-3.2.3
+		sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeCanonicalString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("can't canonicalize %T", v)
+	}
+	return nil
+}
+
+// canonicalNumber format number like ECMAScript `Number.prototype.toString`
+// https://www.rfc-editor.org/rfc/rfc8785#section-3.2.2.3
+func canonicalNumber(f float64) string {
+	if f == 0 {
+		// negative zero is "0" too
+		return "0"
+	}
+	if abs := math.Abs(f); abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	// ECMAScript doesn't pad exponent, "1e-7" instead of "1e-07"
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	return mantissa + "e" + exp[:1] + strings.TrimLeft(exp[1:], "0")
+}
+
+// https://www.rfc-editor.org/rfc/rfc8785#section-3.2.2.2
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// lessUTF16 compare strings by UTF-16 code units
+func lessUTF16(a string, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
@@ -0,0 +1,205 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+)
+
+func (key *UnknownKey) Clone() Key {
+	return &UnknownKey{
+		BaseKey: key.BaseKey.clone(),
+		KeyType: key.KeyType,
+	}
+}
+
+func (key *RSAPrivateKey) Clone() Key {
+	return &RSAPrivateKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     cloneRSAPrivateKey(key.Key),
+	}
+}
+
+func (key *RSAPublicKey) Clone() Key {
+	return &RSAPublicKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     cloneRSAPublicKey(key.Key),
+	}
+}
+
+func (key *ECPrivateKey) Clone() Key {
+	var prik *ecdsa.PrivateKey
+	if key.Key != nil {
+		prik = &ecdsa.PrivateKey{
+			PublicKey: *cloneECPublicKey(&key.Key.PublicKey),
+			D:         cloneBigInt(key.Key.D),
+		}
+	}
+	return &ECPrivateKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     prik,
+	}
+}
+
+func (key *ECPublicKey) Clone() Key {
+	return &ECPublicKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     cloneECPublicKey(key.Key),
+	}
+}
+
+func (key *SymetricKey) Clone() Key {
+	return &SymetricKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     cloneBytes(key.Key),
+	}
+}
+
+func (key *OKPPrivateKey) Clone() Key {
+	return &OKPPrivateKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     ed25519.PrivateKey(cloneBytes(key.Key)),
+	}
+}
+
+func (key *OKPPublicKey) Clone() Key {
+	return &OKPPublicKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     ed25519.PublicKey(cloneBytes(key.Key)),
+	}
+}
+
+// *ecdh.PrivateKey is immutable, so it is shared
+func (key *ECDHPrivateKey) Clone() Key {
+	return &ECDHPrivateKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     key.Key,
+	}
+}
+
+// *ecdh.PublicKey is immutable, so it is shared
+func (key *ECDHPublicKey) Clone() Key {
+	return &ECDHPublicKey{
+		BaseKey: key.BaseKey.clone(),
+		Key:     key.Key,
+	}
+}
+
+// clone deep copy base key, *x509.Certificate in chain is shared because it is used as immutable
+func (bk *BaseKey) clone() BaseKey {
+	res := *bk
+	if bk.KeyOperations != nil {
+		res.KeyOperations = make(KeyOps, len(bk.KeyOperations))
+		for op := range bk.KeyOperations {
+			res.KeyOperations[op] = struct{}{}
+		}
+	}
+	if bk.keyOpsOrder != nil {
+		res.keyOpsOrder = append([]KeyOp(nil), bk.keyOpsOrder...)
+	}
+	if bk.X509URL != nil {
+		u := *bk.X509URL
+		if bk.X509URL.User != nil {
+			user := *bk.X509URL.User
+			u.User = &user
+		}
+		res.X509URL = &u
+	}
+	if bk.X509CertChain != nil {
+		res.X509CertChain = make([]*x509.Certificate, len(bk.X509CertChain))
+		copy(res.X509CertChain, bk.X509CertChain)
+	}
+	res.X509CertThumbprint = cloneBytes(bk.X509CertThumbprint)
+	res.X509CertThumbprintS256 = cloneBytes(bk.X509CertThumbprintS256)
+	if bk.extra != nil {
+		res.extra = cloneJSON(bk.extra).(map[string]interface{})
+	} else {
+		res.extra = make(map[string]interface{})
+	}
+	return res
+}
+
+// cloneJSON deep copy value made by `encoding/json`, other values are shared
+func cloneJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			res[k] = cloneJSON(e)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, e := range v {
+			res[i] = cloneJSON(e)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
+
+func cloneBigInt(n *big.Int) *big.Int {
+	if n == nil {
+		return nil
+	}
+	return new(big.Int).Set(n)
+}
+
+func cloneRSAPublicKey(pubk *rsa.PublicKey) *rsa.PublicKey {
+	if pubk == nil {
+		return nil
+	}
+	return &rsa.PublicKey{N: cloneBigInt(pubk.N), E: pubk.E}
+}
+
+func cloneRSAPrivateKey(prik *rsa.PrivateKey) *rsa.PrivateKey {
+	if prik == nil {
+		return nil
+	}
+	res := &rsa.PrivateKey{
+		PublicKey: *cloneRSAPublicKey(&prik.PublicKey),
+		D:         cloneBigInt(prik.D),
+		Primes:    make([]*big.Int, len(prik.Primes)),
+	}
+	for i, p := range prik.Primes {
+		res.Primes[i] = cloneBigInt(p)
+	}
+	res.Precomputed = rsa.PrecomputedValues{
+		Dp:        cloneBigInt(prik.Precomputed.Dp),
+		Dq:        cloneBigInt(prik.Precomputed.Dq),
+		Qinv:      cloneBigInt(prik.Precomputed.Qinv),
+		CRTValues: make([]rsa.CRTValue, len(prik.Precomputed.CRTValues)),
+	}
+	for i, crt := range prik.Precomputed.CRTValues {
+		res.Precomputed.CRTValues[i] = rsa.CRTValue{
+			Exp:   cloneBigInt(crt.Exp),
+			Coeff: cloneBigInt(crt.Coeff),
+			R:     cloneBigInt(crt.R),
+		}
+	}
+	if prik.Precomputed.Dp != nil {
+		res.Precompute()
+	}
+	return res
+}
+
+func cloneECPublicKey(pubk *ecdsa.PublicKey) *ecdsa.PublicKey {
+	if pubk == nil {
+		return nil
+	}
+	return &ecdsa.PublicKey{
+		Curve: pubk.Curve,
+		X:     cloneBigInt(pubk.X),
+		Y:     cloneBigInt(pubk.Y),
+	}
+}
@@ -0,0 +1,62 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestClone(t *testing.T) {
+	t.Run("same json", func(t *testing.T) {
+		for _, src := range []string{encRSAPri, encRSAPub, encRSAPriMultiPrime, encECPri, encECPub, encOKPPri, encOKPPub, encX25519Pri, encX25519Pub, encBasekeyAll} {
+			k, err := jwk.DecodeKey(strings.NewReader(src))
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			c := k.Clone()
+			if reflect.TypeOf(c) != reflect.TypeOf(k) {
+				t.Fatalf("expected %T, but got %T", k, c)
+			}
+			a, _ := k.MarshalJSON()
+			b, _ := c.MarshalJSON()
+			if !bytes.Equal(a, b) {
+				t.Fatalf("expected %s, but got %s", a, b)
+			}
+		}
+	})
+	t.Run("independent", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(encRSAPri), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.AllowUnknownField = true }))
+		k.Extra()["nested"] = map[string]interface{}{"value": []interface{}{"a"}}
+		k.(*jwk.RSAPrivateKey).KeyOperations[jwk.KeyOpSign] = struct{}{}
+		c := k.Clone().(*jwk.RSAPrivateKey)
+		c.Extra()["nested"].(map[string]interface{})["value"].([]interface{})[0] = "b"
+		c.KeyOperations[jwk.KeyOpVerify] = struct{}{}
+		c.Key.D.SetInt64(1)
+		c.Key.Primes[0].SetInt64(1)
+		if k.Extra()["nested"].(map[string]interface{})["value"].([]interface{})[0] != "a" {
+			t.Fatalf("expected extra unchanged, but changed")
+		}
+		if k.KeyOps().In(jwk.KeyOpVerify) {
+			t.Fatalf("expected key_ops unchanged, but changed")
+		}
+		if err := k.IntoKey().(*rsa.PrivateKey).Validate(); err != nil {
+			t.Fatalf("expected key unchanged, but got %v", err)
+		}
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		var u jwk.UnknownKey
+		if err := json.Unmarshal([]byte(encRSAPri), &u); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		c := u.Clone().(*jwk.UnknownKey)
+		delete(c.Extra(), "n")
+		if u.KeyType != jwk.KeyTypeRSA || u.Extra()["n"] == nil || c.KeyType != jwk.KeyTypeRSA {
+			t.Fatalf("expected rsa members in extra, but got %v", u.Extra())
+		}
+	})
+}
@@ -33,9 +33,30 @@ type (
 type (
 	OptionEncodeSet struct {
 		DisallowUnknownField bool
+		// If AssignKeyID is not nil, it used for `kid` of key which has no `kid`
+		// It doesn't change key in set, only encoded output has `kid`
+		AssignKeyID func(Key) (string, error)
+		// If Canonical is true, output is RFC 8785 JSON Canonicalization Scheme, `key_ops` is sorted unless `OptionEncodeKey.KeyOpsOrder` is set
+		// https://www.rfc-editor.org/rfc/rfc8785
+		Canonical bool
+		// If Indent is not empty, output is pretty-printed with it
+		Indent string
+		// If DisableHTMLEscape is true, `<`, `>` and `&` are not escaped
+		DisableHTMLEscape bool
 	}
 	OptionEncodeKey struct {
 		DisallowUnknownField bool
+		// If ComputeThumbprint is true, missing `x5t`, `x5t#S256` is computed from leaf certificate of `x5c`
+		ComputeThumbprint bool
+		// Order of `key_ops`, default is `KeyOpsUnordered`
+		KeyOpsOrder KeyOpsOrder
+		// If Canonical is true, output is RFC 8785 JSON Canonicalization Scheme, `key_ops` is sorted unless `KeyOpsOrder` is set
+		// https://www.rfc-editor.org/rfc/rfc8785
+		Canonical bool
+		// If Indent is not empty, output is pretty-printed with it
+		Indent string
+		// If DisableHTMLEscape is true, `<`, `>` and `&` are not escaped
+		DisableHTMLEscape bool
 	}
 	OptionDecodeSet struct {
 		DisallowUnknownField bool
@@ -51,6 +72,11 @@ type (
 		DisallowBothUseAndOps    bool
 		IgnorePrecomputed        bool
 		IgnoreValidate           bool
+		// Minimum bit size of RSA modulus, if 0, there is no minimum
+		// 2048 is recommended, https://www.rfc-editor.org/rfc/rfc7518.html#section-3.3
+		MinimumRSABits int
+		// If VerifyX5c is not nil, key with `x5c` is checked by `VerifyX5c` after key decoded
+		VerifyX5c *OptionVerifyX5c
 		// For RSA Private Key, when this true, it ignore JWK define `dp`, `dq` and `qi` and precomputed values.
 		// Recalculate bool
 		// If this Value is true, Decoder don't execute key validation
@@ -66,6 +92,12 @@ type (
 	}
 	OptionFetch struct {
 		Client *http.Client
+		// If AllowInsecureX5u is true, `ResolveX5u` allow `x5u` with `http` scheme
+		AllowInsecureX5u bool
+		// If FillX5c is true, `ResolveX5u` set certificate chain as `x5c` of key
+		FillX5c bool
+		// MaxX5uSize is maximum bytes of `x5u` response, if it is 0, `DefaultMaxX5uSize` is used
+		MaxX5uSize int64
 	}
 )
 
@@ -0,0 +1,125 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+type curveEntry struct {
+	name  string
+	curve elliptic.Curve
+	alg   Algorithm
+}
+
+var (
+	curveMutex sync.RWMutex
+	curveTable []*curveEntry
+)
+
+func init() {
+	RegisterCurve("P-256", elliptic.P256(), AlgorithmES256)
+	RegisterCurve("P-384", elliptic.P384(), AlgorithmES384)
+	RegisterCurve("P-521", elliptic.P521(), AlgorithmES512)
+	RegisterCurve(curveSecp256k1, secp256k1.S256(), AlgorithmES256K)
+}
+
+// RegisterCurve make `EC` key can use curve, `name` is value of `crv` field
+// https://www.rfc-editor.org/rfc/rfc7518.html#section-6.2.1.1
+// `alg` is ECDSA algorithm for that curve, it used for `GuessAlgorithm` and compatibility check
+// If `alg` is not known algorithm, it registered as algorithm for `EC` key
+// If there is already registered curve with same name, it overwrite
+func RegisterCurve(name string, curve elliptic.Curve, alg Algorithm) {
+	setCurve(&curveEntry{name: name, curve: curve, alg: alg})
+	// `_ALG_TABLE` is guarded by its own lock in `registerAlgorithm`
+	if alg.Exist() && !alg.IsKnown() {
+		registerAlgorithm(alg, &AlgorithmInfo{
+			Family:       AlgorithmFamilySignature,
+			KeyTypes:     []KeyType{KeyTypeEC},
+			Curves:       []string{name},
+			KeySize:      curve.Params().BitSize,
+			ExactKeySize: true,
+			KeyUse:       KeyUseSig,
+			KeyOps:       opsSig,
+		})
+	}
+}
+
+func setCurve(entry *curveEntry) {
+	curveMutex.Lock()
+	defer curveMutex.Unlock()
+	for i, e := range curveTable {
+		if e.name == entry.name {
+			curveTable[i] = entry
+			return
+		}
+	}
+	curveTable = append(curveTable, entry)
+}
+
+func lookupCurveByName(name string) *curveEntry {
+	curveMutex.RLock()
+	defer curveMutex.RUnlock()
+	for _, e := range curveTable {
+		if e.name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+func lookupCurve(curve elliptic.Curve) *curveEntry {
+	curveMutex.RLock()
+	defer curveMutex.RUnlock()
+	for _, e := range curveTable {
+		if e.curve == curve {
+			return e
+		}
+	}
+	return nil
+}
+
+func lookupCurveByAlgorithm(alg Algorithm) *curveEntry {
+	curveMutex.RLock()
+	defer curveMutex.RUnlock()
+	for _, e := range curveTable {
+		if e.alg == alg {
+			return e
+		}
+	}
+	return nil
+}
+
+// curveName return `crv` value of curve, when curve is not registered, it use `Curve.Params().Name`
+func curveName(curve elliptic.Curve) string {
+	if e := lookupCurve(curve); e != nil {
+		return e.name
+	}
+	return curve.Params().Name
+}
+
+// isCurveAlgorithm return true when `alg` is ECDSA algorithm of registered curve
+func isCurveAlgorithm(alg Algorithm) bool {
+	curveMutex.RLock()
+	defer curveMutex.RUnlock()
+	for _, e := range curveTable {
+		if e.alg == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// isCompatibleCurve return false only when `alg` is ECDSA algorithm for another curve
+func isCompatibleCurve(key Key, alg Algorithm) bool {
+	pubk, ok := key.IntoPublicKey().(*ecdsa.PublicKey)
+	if !ok || !isCurveAlgorithm(alg) {
+		return true
+	}
+	if e := lookupCurve(pubk.Curve); e != nil {
+		return e.alg == alg
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestRegisterCurve(t *testing.T) {
+	const AlgorithmES224 jwk.Algorithm = "ES224"
+	jwk.RegisterCurve("P-224", elliptic.P224(), AlgorithmES224)
+	t.Run("round trip", func(t *testing.T) {
+		k, err := jwk.NewKey(mustECDSA(elliptic.P224()))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, buf); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		dk, err := jwk.DecodeKey(buf)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		pubk, ok := dk.IntoPublicKey().(*ecdsa.PublicKey)
+		if !ok {
+			t.Fatalf("expected value %T, but got %T", new(ecdsa.PublicKey), dk.IntoPublicKey())
+		}
+		if pubk.Curve != elliptic.P224() {
+			t.Fatalf("expected value %v, but got %v", elliptic.P224().Params().Name, pubk.Curve.Params().Name)
+		}
+	})
+	t.Run("guess", func(t *testing.T) {
+		k, err := jwk.NewKey(mustECDSA(elliptic.P224()))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if alg := jwk.GuessAlgorithm(k); alg != AlgorithmES224 {
+			t.Fatalf("expected value %v, but got %v", AlgorithmES224, alg)
+		}
+	})
+	t.Run("info", func(t *testing.T) {
+		info, ok := AlgorithmES224.Info()
+		if !ok {
+			t.Fatalf("expected true, but got false")
+		}
+		if len(info.Curves) != 1 || info.Curves[0] != "P-224" {
+			t.Fatalf("expected [P-224], but got %v", info.Curves)
+		}
+		if info.KeySize != 224 {
+			t.Fatalf("expected 224, but got %v", info.KeySize)
+		}
+	})
+	t.Run("overwrite", func(t *testing.T) {
+		// test only name, registered curves can't be unregistered
+		const AlgorithmES224A, AlgorithmES224B jwk.Algorithm = "ES224-overwrite-a", "ES224-overwrite-b"
+		jwk.RegisterCurve("P-224-overwrite", elliptic.P224().Params(), AlgorithmES224A)
+		jwk.RegisterCurve("P-224-overwrite", elliptic.P224().Params(), AlgorithmES224B)
+		for _, alg := range []jwk.Algorithm{AlgorithmES224A, AlgorithmES224B} {
+			if info, ok := alg.Info(); !ok || len(info.Curves) != 1 || info.Curves[0] != "P-224-overwrite" {
+				t.Fatalf("expected %v is registered for P-224-overwrite, but got %v, %v", alg, info.Curves, ok)
+			}
+		}
+	})
+	t.Run("compatible algorithm", func(t *testing.T) {
+		if _, err := jwk.NewKey(mustECDSA(elliptic.P224()), AlgorithmES224); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, err := jwk.NewKey(mustECDSA(elliptic.P224()), jwk.AlgorithmES256); !errors.Is(err, jwk.ErrIncompatibleAlgorithm) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrIncompatibleAlgorithm)
+		}
+		if _, err := jwk.NewKey(mustECDSA(elliptic.P256()), jwk.AlgorithmES384); !errors.Is(err, jwk.ErrIncompatibleAlgorithm) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrIncompatibleAlgorithm)
+		}
+		if _, err := jwk.NewKey(mustECDSA(elliptic.P256()), jwk.AlgorithmECDHES); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package jwk
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+)
+
+type (
+	// KeyCodec decode and encode key material of custom `kty`, it is registered by `RegisterKeyType`
+	KeyCodec interface {
+		// Decode make key material from members of JWK, members used for material must be deleted from `data`
+		Decode(data map[string]interface{}) (interface{}, error)
+		// Encode write members of key material into `data`
+		Encode(material interface{}, data map[string]interface{}) error
+		// IntoPublicKey return public key of material
+		IntoPublicKey(material interface{}) crypto.PublicKey
+		// IntoPrivateKey return private key of material, or nil when material is public key
+		IntoPrivateKey(material interface{}) crypto.PrivateKey
+		// RequiredMembers return required members for JWK Thumbprint except `kty`, they must be public members
+		// https://www.rfc-editor.org/rfc/rfc7638#section-3.2
+		RequiredMembers() []string
+	}
+	// CustomKey is key of `kty` registered by `RegisterKeyType`
+	// Key not made by `NewCustomKey` or decoding use codec registered for `KeyType`,
+	// when `KeyType` is not registered, it has no public or private key and can't be encoded
+	CustomKey struct {
+		BaseKey
+		KeyType  KeyType
+		Material interface{}
+		codec    KeyCodec
+	}
+)
+
+type keyTypeEntry struct {
+	kty   KeyType
+	codec KeyCodec
+}
+
+var (
+	keyTypeMutex sync.RWMutex
+	keyTypeTable []*keyTypeEntry
+)
+
+// RegisterKeyType make `kty` decoded as `*CustomKey` using `codec` instead of `*UnknownKey`
+// `EC`, `RSA`, `oct` and `OKP` can't be registered
+// If there is already registered key type with same name, it overwrite
+func RegisterKeyType(kty KeyType, codec KeyCodec) error {
+	if _, ok := orderTable[kty]; ok || len(kty) == 0 {
+		return makeErrors(ErrParameter, ErrUnsupportedKeyType, fmt.Errorf("can't register kty='%s'", kty))
+	}
+	if codec == nil {
+		return makeErrors(ErrParameter, ErrNil, fmt.Errorf("codec is not nilable"))
+	}
+	keyTypeMutex.Lock()
+	defer keyTypeMutex.Unlock()
+	entry := &keyTypeEntry{kty: kty, codec: codec}
+	for i, e := range keyTypeTable {
+		if e.kty == kty {
+			keyTypeTable[i] = entry
+			return nil
+		}
+	}
+	keyTypeTable = append(keyTypeTable, entry)
+	return nil
+}
+
+// NewCustomKey make key of registered `kty` from material
+func NewCustomKey(kty KeyType, material interface{}, options ...OptionalNewKey) (Key, error) {
+	codec := lookupKeyCodec(kty)
+	if codec == nil {
+		return nil, makeErrors(ErrParameter, ErrUnsupportedKeyType, fmt.Errorf("kty='%s' is not registered", kty))
+	}
+	result := &CustomKey{
+		BaseKey:  BaseKey{KeyOperations: map[KeyOp]struct{}{}, extra: map[string]interface{}{}},
+		KeyType:  kty,
+		Material: material,
+		codec:    codec,
+	}
+	for _, opt := range options {
+		if err := opt.WithNewKey(result, result.intoBaseKey()); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func lookupKeyCodec(kty KeyType) KeyCodec {
+	keyTypeMutex.RLock()
+	defer keyTypeMutex.RUnlock()
+	for _, e := range keyTypeTable {
+		if e.kty == kty {
+			return e.codec
+		}
+	}
+	return nil
+}
+
+// keyCodec return codec of key, or registered codec for `KeyType` when key is made without it
+func (key *CustomKey) keyCodec() (KeyCodec, error) {
+	if key.codec != nil {
+		return key.codec, nil
+	}
+	if codec := lookupKeyCodec(key.KeyType); codec != nil {
+		return codec, nil
+	}
+	return nil, makeErrors(ErrCauseCustomKey, ErrUnsupportedKeyType, fmt.Errorf("kty='%s' is not registered", key.KeyType))
+}
+
+// encodeMaterial write members of material into `data` with codec
+func (key *CustomKey) encodeMaterial(data map[string]interface{}) error {
+	codec, err := key.keyCodec()
+	if err != nil {
+		return err
+	}
+	if err := codec.Encode(key.Material, data); err != nil {
+		return makeErrors(ErrCauseCustomKey, err)
+	}
+	return nil
+}
+
+func (key *CustomKey) Kty() KeyType {
+	return key.KeyType
+}
+
+// intoUnknown return nil when material can't be encoded
+func (key *CustomKey) intoUnknown() *UnknownKey {
+	bk := key.BaseKey.clone()
+	if err := key.encodeMaterial(bk.extra); err != nil {
+		return nil
+	}
+	return &UnknownKey{
+		BaseKey: bk,
+		KeyType: key.KeyType,
+	}
+}
+
+func (key *CustomKey) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := EncodeKeyBy(context.Background(), key, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (key *CustomKey) UnmarshalJSON(bts []byte) error {
+	rdr := bytes.NewReader(bts)
+	var opt *OptionDecodeKey
+	ctx := MustGetOptionFromContext(context.Background(), &opt, true)
+	dat, err := DecodeKeyBy(ctx, rdr)
+	if err != nil {
+		return err
+	}
+	ck, ok := dat.(*CustomKey)
+	if !ok {
+		return makeErrors(ErrNotExpectedKty, fmt.Errorf("kty='%s' is not registered", dat.Kty()))
+	}
+	*key = *ck
+	return nil
+}
+
+func (key *CustomKey) IntoKey() interface{} {
+	return key.Material
+}
+
+func (key *CustomKey) IntoPublicKey() crypto.PublicKey {
+	codec, err := key.keyCodec()
+	if err != nil {
+		return nil
+	}
+	return codec.IntoPublicKey(key.Material)
+}
+
+func (key *CustomKey) IntoPrivateKey() crypto.PrivateKey {
+	codec, err := key.keyCodec()
+	if err != nil {
+		return nil
+	}
+	return codec.IntoPrivateKey(key.Material)
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc7638
+func (key *CustomKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	required, err := key.requiredMembers()
+	if err != nil {
+		return nil, err
+	}
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		for k, v := range required {
+			data[k] = v
+		}
+	})
+}
+
+func (key *CustomKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+
+// Public key is made from required members of JWK Thumbprint, if it can't, it return nil
+func (key *CustomKey) Public() Key {
+	codec, err := key.keyCodec()
+	if err != nil {
+		return nil
+	}
+	if codec.IntoPrivateKey(key.Material) == nil {
+		return key.Clone()
+	}
+	required, err := key.requiredMembers()
+	if err != nil {
+		return nil
+	}
+	material, err := codec.Decode(required)
+	if err != nil || codec.IntoPrivateKey(material) != nil {
+		return nil
+	}
+	return &CustomKey{
+		BaseKey:  key.BaseKey.public(),
+		KeyType:  key.KeyType,
+		Material: material,
+		codec:    codec,
+	}
+}
+
+// Material is copied by encoding and decoding with codec, when it can't, material is shared
+func (key *CustomKey) Clone() Key {
+	codec, _ := key.keyCodec()
+	material := key.Material
+	data := make(map[string]interface{})
+	if err := key.encodeMaterial(data); err == nil {
+		if m, err := codec.Decode(data); err == nil {
+			material = m
+		}
+	}
+	return &CustomKey{
+		BaseKey:  key.BaseKey.clone(),
+		KeyType:  key.KeyType,
+		Material: material,
+		codec:    codec,
+	}
+}
+
+func (key *CustomKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+
+func (key *CustomKey) requiredMembers() (map[string]interface{}, error) {
+	codec, err := key.keyCodec()
+	if err != nil {
+		return nil, err
+	}
+	names := codec.RequiredMembers()
+	if len(names) == 0 {
+		return nil, makeErrors(ErrUnsupportedKeyType, fmt.Errorf("can't decide required members of kty='%s'", key.KeyType))
+	}
+	data := make(map[string]interface{})
+	if err := key.encodeMaterial(data); err != nil {
+		return nil, err
+	}
+	required := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		v, ok := data[name]
+		if !ok {
+			return nil, makeErrors(ErrCauseCustomKey, FieldError(name), ErrNotExist)
+		}
+		required[name] = v
+	}
+	return required, nil
+}
+
+// equalCustomMaterial compare encoded members of custom keys
+func equalCustomMaterial(a *CustomKey, b Key) bool {
+	cb, ok := b.(*CustomKey)
+	if !ok {
+		return false
+	}
+	da, db := make(map[string]interface{}), make(map[string]interface{})
+	if a.encodeMaterial(da) != nil || cb.encodeMaterial(db) != nil {
+		return false
+	}
+	return equalExtra(da, db)
+}
@@ -0,0 +1,216 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+const keyTypeTest jwk.KeyType = "ED-TEST"
+
+// testCodec is codec of `ED-TEST`, it is same with `OKP` Ed25519 key but `kty` is different
+type testCodec struct{}
+
+func (testCodec) Decode(data map[string]interface{}) (interface{}, error) {
+	sx, ok := data["x"].(string)
+	if !ok {
+		return nil, jwk.FieldError("x")
+	}
+	delete(data, "x")
+	x, err := base64.RawURLEncoding.DecodeString(sx)
+	if err != nil || len(x) != ed25519.PublicKeySize {
+		return nil, jwk.FieldError("x")
+	}
+	sd, ok := data["d"].(string)
+	if !ok {
+		return ed25519.PublicKey(x), nil
+	}
+	delete(data, "d")
+	d, err := base64.RawURLEncoding.DecodeString(sd)
+	if err != nil || len(d) != ed25519.SeedSize {
+		return nil, jwk.FieldError("d")
+	}
+	return ed25519.NewKeyFromSeed(d), nil
+}
+
+func (testCodec) Encode(material interface{}, data map[string]interface{}) error {
+	switch k := material.(type) {
+	case ed25519.PrivateKey:
+		data["x"] = base64.RawURLEncoding.EncodeToString(k.Public().(ed25519.PublicKey))
+		data["d"] = base64.RawURLEncoding.EncodeToString(k.Seed())
+	case ed25519.PublicKey:
+		data["x"] = base64.RawURLEncoding.EncodeToString(k)
+	default:
+		return errors.New("unknown material")
+	}
+	return nil
+}
+
+func (testCodec) IntoPublicKey(material interface{}) crypto.PublicKey {
+	if k, ok := material.(ed25519.PrivateKey); ok {
+		return k.Public()
+	}
+	return material
+}
+
+func (testCodec) IntoPrivateKey(material interface{}) crypto.PrivateKey {
+	if k, ok := material.(ed25519.PrivateKey); ok {
+		return k
+	}
+	return nil
+}
+
+func (testCodec) RequiredMembers() []string { return []string{"x"} }
+
+const customPri = `{"kty":"ED-TEST","kid":"custom","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+
+func TestRegisterKeyType(t *testing.T) {
+	if err := jwk.RegisterKeyType(keyTypeTest, testCodec{}); err != nil {
+		t.Fatalf("expected <nil>, but got %v", err)
+	}
+	t.Run("builtin", func(t *testing.T) {
+		if err := jwk.RegisterKeyType(jwk.KeyTypeEC, testCodec{}); !errors.Is(err, jwk.ErrUnsupportedKeyType) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrUnsupportedKeyType)
+		}
+	})
+	t.Run("decode", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(customPri))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		ck, ok := k.(*jwk.CustomKey)
+		if !ok {
+			t.Fatalf("expected %T, but got %T", new(jwk.CustomKey), k)
+		}
+		if _, ok := ck.Material.(ed25519.PrivateKey); !ok {
+			t.Fatalf("expected %T, but got %T", ed25519.PrivateKey{}, ck.Material)
+		}
+		if _, ok := k.IntoPublicKey().(ed25519.PublicKey); !ok {
+			t.Fatalf("expected %T, but got %T", ed25519.PublicKey{}, k.IntoPublicKey())
+		}
+		if len(k.Extra()) != 0 {
+			t.Fatalf("expected empty, but got %v", k.Extra())
+		}
+	})
+	t.Run("decode fail", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(`{"kty":"ED-TEST","x":"AAAA"}`))
+		if !errors.Is(err, jwk.ErrCauseCustomKey) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseCustomKey)
+		}
+		if !errors.Is(err, jwk.FieldError("x")) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.FieldError("x"))
+		}
+	})
+	t.Run("round trip", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(customPri))
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, buf); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		dk, err := jwk.DecodeKey(buf)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if !k.Equal(dk, jwk.EqualFull) {
+			t.Fatalf("expected equal, but not")
+		}
+	})
+	t.Run("new", func(t *testing.T) {
+		_, prik, _ := ed25519.GenerateKey(nil)
+		k, err := jwk.NewCustomKey(keyTypeTest, prik, jwk.WithKeyID("new"))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k.Kty() != keyTypeTest || k.Kid() != "new" {
+			t.Fatalf("expected %v, %v, but got %v, %v", keyTypeTest, "new", k.Kty(), k.Kid())
+		}
+		if _, err := jwk.NewCustomKey("unregistered", prik); !errors.Is(err, jwk.ErrUnsupportedKeyType) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrUnsupportedKeyType)
+		}
+	})
+	t.Run("public", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(customPri))
+		pubk := k.Public()
+		if pubk == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if pubk.IntoPrivateKey() != nil {
+			t.Fatalf("expected <nil>, but got %T", pubk.IntoPrivateKey())
+		}
+		if pubk.Kid() != "custom" {
+			t.Fatalf("expected %v, but got %v", "custom", pubk.Kid())
+		}
+		if again := pubk.Public(); again == pubk || !again.Equal(pubk, jwk.EqualFull) {
+			t.Fatalf("expected public key return its clone")
+		}
+	})
+	t.Run("thumbprint", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(customPri))
+		tp, err := k.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		ptp, err := k.Public().Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if !bytes.Equal(tp, ptp) {
+			t.Fatalf("expected same thumbprint for private and public key")
+		}
+	})
+	t.Run("clone", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(customPri)).(*jwk.CustomKey)
+		c := k.Clone().(*jwk.CustomKey)
+		c.Material.(ed25519.PrivateKey)[0] ^= 0xff
+		if k.Equal(c, jwk.EqualMaterial) {
+			t.Fatalf("expected clone is independent, but not")
+		}
+	})
+	t.Run("without codec", func(t *testing.T) {
+		_, prik, _ := ed25519.GenerateKey(nil)
+		// key made by caller use codec registered for kty
+		k := &jwk.CustomKey{KeyType: keyTypeTest, Material: prik}
+		if _, ok := k.IntoPrivateKey().(ed25519.PrivateKey); !ok {
+			t.Fatalf("expected %T, but got %T", ed25519.PrivateKey{}, k.IntoPrivateKey())
+		}
+		if _, err := k.Thumbprint(crypto.SHA256); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, err := k.MarshalJSON(); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		// zero value has no registered codec
+		zero := new(jwk.CustomKey)
+		if zero.IntoPublicKey() != nil || zero.IntoPrivateKey() != nil || zero.Public() != nil {
+			t.Fatalf("expected <nil>, but not")
+		}
+		if _, err := zero.Thumbprint(crypto.SHA256); !errors.Is(err, jwk.ErrUnsupportedKeyType) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrUnsupportedKeyType)
+		}
+		if _, err := zero.MarshalJSON(); !errors.Is(err, jwk.ErrCauseCustomKey) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseCustomKey)
+		}
+		if c := zero.Clone(); c.Kty() != zero.Kty() {
+			t.Fatalf("expected %v, but got %v", zero.Kty(), c.Kty())
+		}
+	})
+	t.Run("sort", func(t *testing.T) {
+		keys := []jwk.Key{
+			jwk.MustDecodeKey(strings.NewReader(`{"kty":"unknown","kid":"custom"}`)),
+			jwk.MustDecodeKey(strings.NewReader(customPri)),
+			jwk.MustKey([]byte("0123456789abcdef0123456789abcdef"), jwk.WithKeyID("custom")),
+		}
+		jwk.SortKey(keys)
+		for i, kty := range []jwk.KeyType{jwk.KeyTypeOctet, keyTypeTest, "unknown"} {
+			if keys[i].Kty() != kty {
+				t.Fatalf("expected %v, but got %v", kty, keys[i].Kty())
+			}
+		}
+	})
+}
@@ -1,9 +1,11 @@
 package jwk
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdh"
 	"crypto/ecdsa"
-	"crypto/elliptic"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/sha1"
 	"crypto/sha256"
@@ -91,7 +93,7 @@ func decodeKeyBy(ctx context.Context, option *OptionDecodeKey, data map[string]i
 		tmp := new(SymetricKey)
 		result = tmp
 		tmp.BaseKey = bkey
-		if err := decodeSymetricKey(&tmp.Key, option, data); err != nil {
+		if err := decodeSymetricKey(&tmp.Key, tmp.Algorithm, option, data); err != nil {
 			return nil, err
 		}
 	case kty == KeyTypeEC:
@@ -130,6 +132,56 @@ func decodeKeyBy(ctx context.Context, option *OptionDecodeKey, data map[string]i
 				return nil, err
 			}
 		}
+	case kty == KeyTypeOKP && data["crv"] == curveX25519:
+		if _, ok := data["d"]; ok {
+			tmp := new(ECDHPrivateKey)
+			result = tmp
+			tmp.BaseKey = bkey
+			if err := decodeECDHPriKey(&tmp.Key, option, data); err != nil {
+				return nil, err
+			}
+		} else {
+			tmp := new(ECDHPublicKey)
+			result = tmp
+			tmp.BaseKey = bkey
+			if err := decodeECDHPubKey(&tmp.Key, option, data); err != nil {
+				return nil, err
+			}
+		}
+	case kty == KeyTypeOKP && (data["crv"] == curveEd448 || data["crv"] == curveX448):
+		// registered curves but not supported, same as baseline of unsupported key type
+		tmp := new(UnknownKey)
+		result = tmp
+		tmp.KeyType = kty
+		tmp.BaseKey = bkey
+		decodeUnknownKey(tmp, option, data)
+	case kty == KeyTypeOKP:
+		if _, ok := data["d"]; ok {
+			tmp := new(OKPPrivateKey)
+			result = tmp
+			tmp.BaseKey = bkey
+			if err := decodeOKPPriKey(&tmp.Key, option, data); err != nil {
+				return nil, err
+			}
+		} else {
+			tmp := new(OKPPublicKey)
+			result = tmp
+			tmp.BaseKey = bkey
+			if err := decodeOKPPubKey(&tmp.Key, option, data); err != nil {
+				return nil, err
+			}
+		}
+	case lookupKeyCodec(kty) != nil:
+		tmp := new(CustomKey)
+		result = tmp
+		tmp.KeyType = kty
+		tmp.BaseKey = bkey
+		tmp.codec = lookupKeyCodec(kty)
+		material, err := tmp.codec.Decode(data)
+		if err != nil {
+			return nil, makeErrors(ErrRequirement, ErrCauseCustomKey, err)
+		}
+		tmp.Material = material
 	default:
 		tmp := new(UnknownKey)
 		result = tmp
@@ -137,6 +189,11 @@ func decodeKeyBy(ctx context.Context, option *OptionDecodeKey, data map[string]i
 		tmp.BaseKey = bkey
 		decodeUnknownKey(tmp, option, data)
 	}
+	if option.VerifyX5c != nil && len(result.X5c()) > 0 {
+		if err := VerifyX5c(result, option.VerifyX5c); err != nil {
+			return nil, makeErrors(ErrRequirement, ErrCauseOption, err)
+		}
+	}
 	//
 	if option.AllowUnknownField {
 		m := result.Extra()
@@ -186,6 +243,7 @@ func decodeBaseKey(bkey *BaseKey, option *OptionDecodeKey, data map[string]inter
 				}
 			}
 			m[op] = struct{}{}
+			bkey.keyOpsOrder = append(bkey.keyOpsOrder, op)
 		}
 		bkey.KeyOperations = KeyOps(m)
 		if !bkey.KeyOperations.IsValidCombination() {
@@ -229,8 +287,7 @@ func decodeBaseKey(bkey *BaseKey, option *OptionDecodeKey, data map[string]inter
 	sx5u, x5uerr := utilConsumeURL(data, "x5u")
 	if x5uerr == nil {
 		bkey.X509URL = sx5u
-		// TODO : Validate x5u
-
+		// certificate chain of url is fetched and verified by `ResolveX5u`
 	} else {
 		if !errors.Is(x5uerr, ErrNotExist) {
 			return makeErrors(ErrRequirement, FieldError("x5u"), x5uerr)
@@ -251,7 +308,7 @@ func decodeBaseKey(bkey *BaseKey, option *OptionDecodeKey, data map[string]inter
 			}
 			bkey.X509CertChain[i] = cert
 		}
-		// TODO : Validate x5c
+		// chain is verified by `VerifyX5c` when `OptionDecodeKey.VerifyX5c` is set
 	} else {
 		if !errors.Is(x5cerr, ErrNotExist) {
 			return makeErrors(ErrRequirement, FieldError("x5c"), x5cerr)
@@ -264,7 +321,11 @@ func decodeBaseKey(bkey *BaseKey, option *OptionDecodeKey, data map[string]inter
 			return makeErrors(ErrRequirement, FieldError("x5t"), ErrSHA1Size, fmt.Errorf("expected length %d, but got %d", sha1.Size, len(bx5t)))
 		}
 		bkey.X509CertThumbprint = bx5t
-		// TODO : Validate x5t
+		if !option.IgnoreValidate && len(bkey.X509CertChain) > 0 {
+			if sum := sha1.Sum(bkey.X509CertChain[0].Raw); !bytes.Equal(sum[:], bx5t) {
+				return makeErrors(ErrRequirement, FieldError("x5t"), ErrX5tMismatch)
+			}
+		}
 	} else {
 		if !errors.Is(x5terr, ErrNotExist) {
 			return makeErrors(ErrRequirement, FieldError("x5t"), x5terr)
@@ -277,7 +338,11 @@ func decodeBaseKey(bkey *BaseKey, option *OptionDecodeKey, data map[string]inter
 			return makeErrors(ErrRequirement, FieldError("x5t#S256"), ErrSHA256Size, fmt.Errorf("expected length %d, but got %d", sha256.Size, len(bx5ts)))
 		}
 		bkey.X509CertThumbprintS256 = bx5ts
-		// TODO : Validate x5t#S256
+		if !option.IgnoreValidate && len(bkey.X509CertChain) > 0 {
+			if sum := sha256.Sum256(bkey.X509CertChain[0].Raw); !bytes.Equal(sum[:], bx5ts) {
+				return makeErrors(ErrRequirement, FieldError("x5t#S256"), ErrX5tMismatch)
+			}
+		}
 	} else {
 		if !errors.Is(x5tserr, ErrNotExist) {
 			return makeErrors(ErrRequirement, FieldError("x5t#S256"), x5tserr)
@@ -286,12 +351,24 @@ func decodeBaseKey(bkey *BaseKey, option *OptionDecodeKey, data map[string]inter
 	return nil
 }
 
-func decodeSymetricKey(key *[]byte, option *OptionDecodeKey, data map[string]interface{}) error {
+// `alg` is used for validate length of `k`
+func decodeSymetricKey(key *[]byte, alg Algorithm, option *OptionDecodeKey, data map[string]interface{}) error {
 	if bn, err := utilConsumeB64url(data, "k"); err == nil {
 		*key = bn
 	} else {
 		return makeErrors(ErrRequirement, ErrCauseSymetricKey, FieldError("k"), err)
 	}
+	if !option.IgnoreValidate {
+		// HMAC key must be same or larger than hash output, AES key must be exact size
+		// https://www.rfc-editor.org/rfc/rfc7518.html#section-3.2
+		switch size, isMinimum := symetricKeySize(alg); {
+		case size == 0:
+		case isMinimum && len(*key) < size:
+			return makeErrors(ErrRequirement, ErrCauseSymetricKey, FieldError("k"), ErrCauseSymetricValidate, ErrInsufficientKeySize, fmt.Errorf("alg='%s' expected length %d or larger, but got %d", alg, size, len(*key)))
+		case !isMinimum && len(*key) != size:
+			return makeErrors(ErrRequirement, ErrCauseSymetricKey, FieldError("k"), ErrCauseSymetricValidate, ErrInsufficientKeySize, fmt.Errorf("alg='%s' expected length %d, but got %d", alg, size, len(*key)))
+		}
+	}
 	return nil
 }
 
@@ -313,10 +390,27 @@ func decodeRSAPubKey(key *rsa.PublicKey, option *OptionDecodeKey, data map[strin
 	}
 	// public E
 	if be, err := utilConsumeB64url(data, "e"); err == nil {
-		key.E = int(new(big.Int).SetBytes(be).Int64())
+		e := new(big.Int).SetBytes(be)
+		// crypto/rsa allow only 2^31 - 1 or smaller
+		if e.BitLen() > 31 {
+			return makeErrors(ErrRequirement, ErrCauseRSAPublicKey, FieldError("e"), fmt.Errorf("'e' is too large, %d bits", e.BitLen()))
+		}
+		key.E = int(e.Int64())
 	} else {
 		return makeErrors(ErrRequirement, ErrCauseRSAPublicKey, FieldError("e"), err)
 	}
+	if !option.IgnoreValidate {
+		// modulus of real key is always odd
+		if key.N.Bit(0) == 0 || key.N.Cmp(big.NewInt(int64(key.E))) <= 0 {
+			return makeErrors(ErrRequirement, ErrCauseRSAPublicKey, FieldError("n"), ErrCauseRSAValidate, fmt.Errorf("'n' must be odd and larger than 'e'"))
+		}
+		if minimum := option.MinimumRSABits; key.N.BitLen() < minimum {
+			return makeErrors(ErrRequirement, ErrCauseRSAPublicKey, FieldError("n"), ErrCauseRSAValidate, ErrInsufficientKeySize, fmt.Errorf("expected %d bits or larger, but got %d", minimum, key.N.BitLen()))
+		}
+		if key.E <= 1 || key.E%2 == 0 {
+			return makeErrors(ErrRequirement, ErrCauseRSAPublicKey, FieldError("e"), ErrCauseRSAValidate, fmt.Errorf("'e' must be odd and larger than 1, but got %d", key.E))
+		}
+	}
 	return nil
 }
 
@@ -347,6 +441,51 @@ func decodeRSAPriKey(key *rsa.PrivateKey, option *OptionDecodeKey, data map[stri
 	} else {
 		return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("q"), err)
 	}
+	// https://www.rfc-editor.org/rfc/rfc7518.html#section-6.3.2.7
+	var crtValues []rsa.CRTValue
+	if aoth, err := utilConsumeArrMap(data, "oth"); err == nil {
+		if len(aoth) == 0 {
+			return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), ErrInvalidOtherPrimes, fmt.Errorf("'oth' must not be empty"))
+		}
+		r := new(big.Int).Mul(key.Primes[0], key.Primes[1])
+		crtValues = make([]rsa.CRTValue, len(aoth))
+		for i, oth := range aoth {
+			br, err := utilConsumeB64url(oth, "r")
+			if err != nil {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("r"), ErrInvalidOtherPrimes, err)
+			}
+			bd, err := utilConsumeB64url(oth, "d")
+			if err != nil {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("d"), ErrInvalidOtherPrimes, err)
+			}
+			bt, err := utilConsumeB64url(oth, "t")
+			if err != nil {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("t"), ErrInvalidOtherPrimes, err)
+			}
+			prime, exp, coeff := new(big.Int).SetBytes(br), new(big.Int).SetBytes(bd), new(big.Int).SetBytes(bt)
+			// 'r' must be factor not shared with previous primes, otherwise validation below divide by zero
+			if prime.Cmp(big.NewInt(1)) <= 0 || new(big.Int).GCD(nil, nil, prime, r).Cmp(big.NewInt(1)) != 0 {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("r"), ErrInvalidOtherPrimes, fmt.Errorf("'r' must be greater than 1 and coprime with other primes"))
+			}
+			if exp.Sign() <= 0 {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("d"), ErrInvalidOtherPrimes, fmt.Errorf("'d' must be positive"))
+			}
+			if coeff.Sign() <= 0 {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("t"), ErrInvalidOtherPrimes, fmt.Errorf("'t' must be positive"))
+			}
+			key.Primes = append(key.Primes, prime)
+			crtValues[i] = rsa.CRTValue{
+				Exp:   exp,
+				Coeff: coeff,
+				R:     new(big.Int).Set(r),
+			}
+			r.Mul(r, prime)
+		}
+	} else {
+		if !errors.Is(err, ErrNotExist) {
+			return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), ErrInvalidOtherPrimes, err)
+		}
+	}
 	if option.IgnorePrecomputed {
 		delete(data, "dp")
 		delete(data, "dq")
@@ -368,23 +507,19 @@ func decodeRSAPriKey(key *rsa.PrivateKey, option *OptionDecodeKey, data map[stri
 		} else {
 			return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("qi"), err)
 		}
+		key.Precomputed.CRTValues = crtValues
+	}
+	if !option.IgnoreValidate {
+		for i, crt := range crtValues {
+			prime := key.Primes[i+2]
+			if crt.Exp.Cmp(new(big.Int).Mod(key.D, new(big.Int).Sub(prime, big.NewInt(1)))) != 0 {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("d"), ErrInvalidOtherPrimes, fmt.Errorf("'d' is not matched with 'r'"))
+			}
+			if new(big.Int).Mod(new(big.Int).Mul(crt.Coeff, crt.R), prime).Cmp(big.NewInt(1)) != 0 {
+				return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, FieldError("oth"), IndexError(i), FieldError("t"), ErrInvalidOtherPrimes, fmt.Errorf("'t' is not matched with 'r'"))
+			}
+		}
 	}
-	// TODO : data["oth"]
-	// var oth []*big.Int
-	// if both, err := utilConsumeArrMap(data, "oth"); err == nil {
-	// 	oth = make([]*big.Int, len(both))
-	// 	for i, v := range both {
-	// 		oth[i]
-	// 	}
-	// } else {
-	// 	if errors.Is(err, errKeyNotExist) {
-	// 		// this value is optional.
-	// 	} else if errors.Is(err, errInvalidType) {
-	// 		return nil, errorCause(ErrKeyPubRSAFailed, "'oth' must be string")
-	// 	} else {
-	// 		return nil, &Error{Cause: ErrKeyPubRSAFailed, Detail: err}
-	// 	}
-	// }
 	if !option.IgnoreValidate {
 		if err := key.Validate(); err != nil {
 			return makeErrors(ErrRequirement, ErrCauseRSAPrivateKey, ErrCauseRSAValidate, err)
@@ -397,14 +532,9 @@ func decodeRSAPriKey(key *rsa.PrivateKey, option *OptionDecodeKey, data map[stri
 func decodeECPubKey(key *ecdsa.PublicKey, option *OptionDecodeKey, data map[string]interface{}) error {
 
 	if curve, err := utilConsumeStr(data, "crv"); err == nil {
-		switch curve {
-		case "P-256":
-			key.Curve = elliptic.P256()
-		case "P-384":
-			key.Curve = elliptic.P384()
-		case "P-521":
-			key.Curve = elliptic.P521()
-		default:
+		if e := lookupCurveByName(curve); e != nil {
+			key.Curve = e.curve
+		} else {
 			return makeErrors(ErrRequirement, ErrCauseECPublicKey, FieldError("crv"), ErrCauseUnknown, fmt.Errorf("unknown curve '%s'", curve))
 		}
 	} else {
@@ -427,6 +557,15 @@ func decodeECPubKey(key *ecdsa.PublicKey, option *OptionDecodeKey, data map[stri
 	} else {
 		return makeErrors(ErrRequirement, ErrCauseECPublicKey, FieldError("y"), err)
 	}
+	if !option.IgnoreValidate {
+		p := key.Curve.Params().P
+		if key.X.Sign() == 0 && key.Y.Sign() == 0 {
+			return makeErrors(ErrRequirement, ErrCauseECPublicKey, ErrCauseECValidate, fmt.Errorf("point is identity"))
+		}
+		if key.X.Cmp(p) >= 0 || key.Y.Cmp(p) >= 0 || !key.Curve.IsOnCurve(key.X, key.Y) {
+			return makeErrors(ErrRequirement, ErrCauseECPublicKey, ErrCauseECValidate, fmt.Errorf("point is not on curve"))
+		}
+	}
 	return nil
 }
 
@@ -440,12 +579,108 @@ func decodeECPriKey(key *ecdsa.PrivateKey, option *OptionDecodeKey, data map[str
 	expectedLength := (key.Curve.Params().BitSize + 7) / 8
 	if d, err := utilConsumeB64url(data, "d"); err == nil {
 		if len(d) != expectedLength {
-			return makeErrors(ErrRequirement, ErrCauseECPublicKey, FieldError("y"), ErrECInvalidBytesLength, fmt.Errorf("expected length %d, but got %d", expectedLength, len(d)))
+			return makeErrors(ErrRequirement, ErrCauseECPrivateKey, FieldError("d"), ErrECInvalidBytesLength, fmt.Errorf("expected length %d, but got %d", expectedLength, len(d)))
 		}
 		key.D = new(big.Int).SetBytes(d)
 	} else {
 		return makeErrors(ErrRequirement, ErrCauseECPrivateKey, FieldError("d"), err)
 	}
+	if !option.IgnoreValidate {
+		if key.D.Sign() <= 0 || key.D.Cmp(key.Curve.Params().N) >= 0 {
+			return makeErrors(ErrRequirement, ErrCauseECPrivateKey, FieldError("d"), ErrCauseECValidate, fmt.Errorf("'d' is out of range"))
+		}
+		if x, y := key.Curve.ScalarBaseMult(key.D.Bytes()); x.Cmp(key.X) != 0 || y.Cmp(key.Y) != 0 {
+			return makeErrors(ErrRequirement, ErrCauseECPrivateKey, FieldError("d"), ErrCauseECValidate, fmt.Errorf("'d' is not matched with 'x', 'y'"))
+		}
+	}
+	return nil
+}
+
+// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+func decodeOKPPubKey(key *ed25519.PublicKey, option *OptionDecodeKey, data map[string]interface{}) error {
+	if curve, err := utilConsumeStr(data, "crv"); err == nil {
+		if curve != curveEd25519 {
+			return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("crv"), ErrCauseUnknown, fmt.Errorf("unknown curve '%s'", curve))
+		}
+	} else {
+		return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("crv"), err)
+	}
+	if x, err := utilConsumeB64url(data, "x"); err == nil {
+		if len(x) != ed25519.PublicKeySize {
+			return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("x"), ErrOKPInvalidBytesLength, fmt.Errorf("expected length %d, but got %d", ed25519.PublicKeySize, len(x)))
+		}
+		*key = ed25519.PublicKey(x)
+	} else {
+		return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("x"), err)
+	}
+	return nil
+}
+
+// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+func decodeOKPPriKey(key *ed25519.PrivateKey, option *OptionDecodeKey, data map[string]interface{}) error {
+	var pubk ed25519.PublicKey
+	if err := decodeOKPPubKey(&pubk, option, data); err != nil {
+		replaceErrors(err, ErrCauseOKPPublicKey, ErrCauseOKPPrivateKey)
+		return err
+	}
+	if d, err := utilConsumeB64url(data, "d"); err == nil {
+		if len(d) != ed25519.SeedSize {
+			return makeErrors(ErrRequirement, ErrCauseOKPPrivateKey, FieldError("d"), ErrOKPInvalidBytesLength, fmt.Errorf("expected length %d, but got %d", ed25519.SeedSize, len(d)))
+		}
+		*key = ed25519.NewKeyFromSeed(d)
+	} else {
+		return makeErrors(ErrRequirement, ErrCauseOKPPrivateKey, FieldError("d"), err)
+	}
+	if !option.IgnoreValidate {
+		if !bytes.Equal(key.Public().(ed25519.PublicKey), pubk) {
+			return makeErrors(ErrRequirement, ErrCauseOKPPrivateKey, ErrCauseOKPValidate, fmt.Errorf("'x' is not matched with 'd'"))
+		}
+	}
+	return nil
+}
+
+// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+func decodeECDHPubKey(key **ecdh.PublicKey, option *OptionDecodeKey, data map[string]interface{}) error {
+	if curve, err := utilConsumeStr(data, "crv"); err == nil {
+		if curve != curveX25519 {
+			return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("crv"), ErrCauseUnknown, fmt.Errorf("unknown curve '%s'", curve))
+		}
+	} else {
+		return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("crv"), err)
+	}
+	if x, err := utilConsumeB64url(data, "x"); err == nil {
+		pubk, err := ecdh.X25519().NewPublicKey(x)
+		if err != nil {
+			return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("x"), ErrOKPInvalidBytesLength, err)
+		}
+		*key = pubk
+	} else {
+		return makeErrors(ErrRequirement, ErrCauseOKPPublicKey, FieldError("x"), err)
+	}
+	return nil
+}
+
+// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+func decodeECDHPriKey(key **ecdh.PrivateKey, option *OptionDecodeKey, data map[string]interface{}) error {
+	var pubk *ecdh.PublicKey
+	if err := decodeECDHPubKey(&pubk, option, data); err != nil {
+		replaceErrors(err, ErrCauseOKPPublicKey, ErrCauseOKPPrivateKey)
+		return err
+	}
+	if d, err := utilConsumeB64url(data, "d"); err == nil {
+		prik, err := ecdh.X25519().NewPrivateKey(d)
+		if err != nil {
+			return makeErrors(ErrRequirement, ErrCauseOKPPrivateKey, FieldError("d"), ErrOKPInvalidBytesLength, err)
+		}
+		*key = prik
+	} else {
+		return makeErrors(ErrRequirement, ErrCauseOKPPrivateKey, FieldError("d"), err)
+	}
+	if !option.IgnoreValidate {
+		if !(*key).PublicKey().Equal(pubk) {
+			return makeErrors(ErrRequirement, ErrCauseOKPPrivateKey, ErrCauseOKPValidate, fmt.Errorf("'x' is not matched with 'd'"))
+		}
+	}
 	return nil
 }
 
@@ -2,9 +2,12 @@ package jwk_test
 
 import (
 	"context"
+	"crypto/ecdh"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	_ "embed"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -88,6 +91,8 @@ var (
 	basekeyX5tNotString string
 	//go:embed embeding/basekey-x5t-not-b64.json
 	basekeyX5tNotB64 string
+	//go:embed embeding/basekey-x5t-mismatch.json
+	basekeyX5tMismatch string
 
 	//go:embed embeding/basekey-x5ts256-tempvalid.json
 	basekeyX5tS256Valid string
@@ -97,6 +102,8 @@ var (
 	basekeyX5tS256NotString string
 	//go:embed embeding/basekey-x5ts256-not-b64.json
 	basekeyX5tS256NotB64 string
+	//go:embed embeding/basekey-x5ts256-mismatch.json
+	basekeyX5tS256Mismatch string
 )
 
 // Unknown key
@@ -111,12 +118,18 @@ var (
 	octetValid string
 	//go:embed embeding/octet-without-k.json
 	octetWithoutK string
+	//go:embed embeding/octet-hs256-short-k.json
+	octetHS256ShortK string
+	//go:embed embeding/octet-a128kw-invalid-length-k.json
+	octetA128KWInvalidLengthK string
 )
 
 // EC public key
 var (
 	//go:embed embeding/ec-pub-valid.json
 	ecPubValid string
+	//go:embed embeding/ec-pub-valid-secp256k1.json
+	ecPubValidSecp256k1 string
 	//go:embed embeding/ec-pub-unknown-crv.json
 	ecPubUnknownCrv string
 	//go:embed embeding/ec-pub-without-crv.json
@@ -125,6 +138,10 @@ var (
 	ecPubWithoutX string
 	//go:embed embeding/ec-pub-without-y.json
 	ecPubWithoutY string
+	//go:embed embeding/ec-pub-not-on-curve.json
+	ecPubNotOnCurve string
+	//go:embed embeding/ec-pub-identity.json
+	ecPubIdentity string
 )
 
 // EC private key
@@ -135,6 +152,8 @@ var (
 	ecPriValidP384 string
 	//go:embed embeding/ec-pri-valid-p521.json
 	ecPriValidP521 string
+	//go:embed embeding/ec-pri-valid-secp256k1.json
+	ecPriValidSecp256k1 string
 	//go:embed embeding/ec-pri-unknown-crv.json
 	ecPriUnknownCrv string
 	//go:embed embeding/ec-pri-without-crv.json
@@ -152,6 +171,10 @@ var (
 	ecPriInvalidLengthY string
 	//go:embed embeding/ec-pri-invalid-length-x.json
 	ecPriInvalidLengthX string
+	//go:embed embeding/ec-pri-unmatched-d.json
+	ecPriUnmatchedD string
+	//go:embed embeding/ec-pri-out-of-range-d.json
+	ecPriOutOfRangeD string
 )
 
 // RSA public key
@@ -162,6 +185,12 @@ var (
 	rsaPubWithoutN string
 	//go:embed embeding/rsa-pub-without-e.json
 	rsaPubWithoutE string
+	//go:embed embeding/rsa-pub-small-n.json
+	rsaPubSmallN string
+	//go:embed embeding/rsa-pub-even-e.json
+	rsaPubEvenE string
+	//go:embed embeding/rsa-pub-overflow-e.json
+	rsaPubOverflowE string
 )
 
 // RSA public key
@@ -190,6 +219,36 @@ var (
 	rsaPriWithoutQi string
 	//go:embed embeding/rsa-pri-no-precomputed.json
 	rsaPriNoPrecomputed string
+	//go:embed embeding/rsa-pri-valid-multi-prime.json
+	rsaPriValidMultiPrime string
+	//go:embed embeding/rsa-pri-multi-prime-without-r.json
+	rsaPriMultiPrimeWithoutR string
+	//go:embed embeding/rsa-pri-multi-prime-invalid-t.json
+	rsaPriMultiPrimeInvalidT string
+	//go:embed embeding/rsa-pri-multi-prime-invalid-r.json
+	rsaPriMultiPrimeInvalidR string
+	//go:embed embeding/rsa-pri-multi-prime-invalid-oth.json
+	rsaPriMultiPrimeInvalidOth string
+)
+
+// OKP key
+var (
+	//go:embed embeding/okp-pri-valid.json
+	okpPriValid string
+	//go:embed embeding/okp-pri-without-x.json
+	okpPriWithoutX string
+	//go:embed embeding/okp-pri-unmatched-x.json
+	okpPriUnmatchedX string
+	//go:embed embeding/okp-pub-valid.json
+	okpPubValid string
+	//go:embed embeding/okp-pub-unknown-crv.json
+	okpPubUnknownCrv string
+	//go:embed embeding/okp-pub-invalid-length-x.json
+	okpPubInvalidLengthX string
+	//go:embed embeding/okp-x25519-pri-valid.json
+	okpX25519PriValid string
+	//go:embed embeding/okp-x25519-pub-valid.json
+	okpX25519PubValid string
 )
 
 // Set
@@ -200,6 +259,8 @@ var (
 	setInvalidJSON string
 	//go:embed embeding/set-with-invalid-key.json
 	setWithInvalidKey string
+	//go:embed embeding/set-valid-ed448.json
+	setValidEd448 string
 )
 
 func withoutField(fieldname string, t *testing.T, file io.Reader) {
@@ -598,6 +659,21 @@ func TestDecodeX5t(t *testing.T) {
 			t.Fatalf("expected %v is %v, but not", err, jwk.ErrSHA1Size)
 		}
 	})
+	t.Run("mismatch", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(basekeyX5tMismatch))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrX5tMismatch) || !errors.Is(err, jwk.FieldError("x5t")) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrX5tMismatch)
+		}
+	})
+	t.Run("mismatch ignore validate", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(basekeyX5tMismatch), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true }))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
 }
 
 func TestDecodeX5tS256(t *testing.T) {
@@ -635,6 +711,21 @@ func TestDecodeX5tS256(t *testing.T) {
 			t.Fatalf("expected %v is %v, but not", err, jwk.ErrSHA256Size)
 		}
 	})
+	t.Run("mismatch", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(basekeyX5tS256Mismatch))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrX5tMismatch) || !errors.Is(err, jwk.FieldError("x5t#S256")) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrX5tMismatch)
+		}
+	})
+	t.Run("mismatch ignore validate", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(basekeyX5tS256Mismatch), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true }))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
 }
 
 func TestDecodeX5tSHA256(t *testing.T) {
@@ -714,6 +805,36 @@ func TestDecodeOctet(t *testing.T) {
 			t.Fatalf("expected %v is %v, but not", err, jwk.FieldError("k"))
 		}
 	})
+	t.Run("short k", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(octetHS256ShortK))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseSymetricValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseSymetricValidate)
+		}
+		if !errors.Is(err, jwk.ErrInsufficientKeySize) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInsufficientKeySize)
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(octetHS256ShortK), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("invalid length k", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(octetA128KWInvalidLengthK))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseSymetricValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseSymetricValidate)
+		}
+		if !errors.Is(err, jwk.ErrInsufficientKeySize) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInsufficientKeySize)
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(octetA128KWInvalidLengthK), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
 }
 
 func TestDecodeECPri(t *testing.T) {
@@ -758,6 +879,22 @@ func TestDecodeECPri(t *testing.T) {
 			t.Fatalf("expected value %T, but got %T", new(ecdsa.PublicKey), k.IntoPublicKey())
 		}
 	})
+	t.Run("valid secp256k1", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(ecPriValidSecp256k1))
+		if err != nil {
+			t.Fatalf("expected value <nil>, but got %v", err)
+		}
+		if pubk, ok := k.IntoPublicKey().(*ecdsa.PublicKey); ok {
+			if pubk.Curve.Params().Name != "secp256k1" {
+				t.Fatalf("expected value %v, but got %v", "secp256k1", pubk.Curve.Params().Name)
+			}
+		} else {
+			t.Fatalf("expected value %T, but got %T", new(ecdsa.PublicKey), k.IntoPublicKey())
+		}
+		if alg := jwk.GuessAlgorithm(k); alg != jwk.AlgorithmES256K {
+			t.Fatalf("expected value %v, but got %v", jwk.AlgorithmES256K, alg)
+		}
+	})
 	withoutField("crv", t, strings.NewReader(ecPriWithoutCrv))
 	withoutField("x", t, strings.NewReader(ecPriWithoutX))
 	withoutField("y", t, strings.NewReader(ecPriWithoutY))
@@ -819,6 +956,27 @@ func TestDecodeECPri(t *testing.T) {
 			t.Fatalf("expected %v is %v, but not", err, jwk.ErrECInvalidBytesLength)
 		}
 	})
+	t.Run("unmatched d", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(ecPriUnmatchedD))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseECValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseECValidate)
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(ecPriUnmatchedD), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("out of range d", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(ecPriOutOfRangeD))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseECValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseECValidate)
+		}
+	})
 }
 
 func TestDecodeECPub(t *testing.T) {
@@ -831,6 +989,15 @@ func TestDecodeECPub(t *testing.T) {
 			t.Fatalf("expected value %v, but got %v", jwk.KeyTypeEC, k.Kty())
 		}
 	})
+	t.Run("valid secp256k1", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(ecPubValidSecp256k1))
+		if err != nil {
+			t.Fatalf("expected value <nil>, but got %v", err)
+		}
+		if k.Kty() != jwk.KeyTypeEC {
+			t.Fatalf("expected value %v, but got %v", jwk.KeyTypeEC, k.Kty())
+		}
+	})
 	withoutField("crv", t, strings.NewReader(ecPubWithoutCrv))
 	withoutField("x", t, strings.NewReader(ecPubWithoutX))
 	withoutField("y", t, strings.NewReader(ecPubWithoutY))
@@ -849,6 +1016,27 @@ func TestDecodeECPub(t *testing.T) {
 			t.Fatalf("expected is jwk.ErrCauseUnknown")
 		}
 	})
+	t.Run("not on curve", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(ecPubNotOnCurve))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseECValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseECValidate)
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(ecPubNotOnCurve), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("identity", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(ecPubIdentity))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseECValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseECValidate)
+		}
+	})
 }
 
 func TestDecodeRSAPub(t *testing.T) {
@@ -863,6 +1051,57 @@ func TestDecodeRSAPub(t *testing.T) {
 	})
 	withoutField("n", t, strings.NewReader(rsaPubWithoutN))
 	withoutField("e", t, strings.NewReader(rsaPubWithoutE))
+	t.Run("small n", func(t *testing.T) {
+		if _, err := jwk.DecodeKey(strings.NewReader(rsaPubSmallN)); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		_, err := jwk.DecodeKey(strings.NewReader(rsaPubSmallN), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.MinimumRSABits = 2048 }))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseRSAValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseRSAValidate)
+		}
+		if !errors.Is(err, jwk.ErrInsufficientKeySize) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInsufficientKeySize)
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(rsaPubSmallN), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.MinimumRSABits = 1024 })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(rsaPubSmallN), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.MinimumRSABits, value.IgnoreValidate = 2048, true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("even n", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(`{"kty":"RSA","n":"ma2uRyBeSEOatGuDpCiV9oIxlDWix_KypDYuhQfEzqI","e":"AQAB"}`))
+		if !errors.Is(err, jwk.ErrCauseRSAValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseRSAValidate)
+		}
+	})
+	t.Run("even e", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(rsaPubEvenE))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseRSAValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseRSAValidate)
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(rsaPubEvenE), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("overflow e", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(rsaPubOverflowE))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.FieldError("e")) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.FieldError("e"))
+		}
+		if _, err := jwk.DecodeKey(strings.NewReader(rsaPubOverflowE), jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.IgnoreValidate = true })); err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+	})
 }
 
 func TestDecodeRSAPri(t *testing.T) {
@@ -900,7 +1139,67 @@ func TestDecodeRSAPri(t *testing.T) {
 	withoutField("dp", t, strings.NewReader(rsaPriWithoutDp))
 	withoutField("dq", t, strings.NewReader(rsaPriWithoutDq))
 	withoutField("qi", t, strings.NewReader(rsaPriWithoutQi))
-	// TODO : oth field testing
+	t.Run("oth", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(rsaPriValidMultiPrime))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		prik := k.IntoKey().(*rsa.PrivateKey)
+		if len(prik.Primes) != 3 {
+			t.Fatalf("expected %d primes, but got %d", 3, len(prik.Primes))
+		}
+		if len(prik.Precomputed.CRTValues) != 1 {
+			t.Fatalf("expected %d crt values, but got %d", 1, len(prik.Precomputed.CRTValues))
+		}
+		checkJWT(t, jwk.MustKey(k, jwk.AlgorithmRS256))
+	})
+	t.Run("oth without r", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(rsaPriMultiPrimeWithoutR))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrInvalidOtherPrimes) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInvalidOtherPrimes)
+		}
+		if !errors.Is(err, jwk.FieldError("r")) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.FieldError("r"))
+		}
+	})
+	t.Run("oth invalid t", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(rsaPriMultiPrimeInvalidT))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrInvalidOtherPrimes) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInvalidOtherPrimes)
+		}
+		if !errors.Is(err, jwk.FieldError("t")) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.FieldError("t"))
+		}
+	})
+	t.Run("oth invalid r", func(t *testing.T) {
+		for _, ignore := range []bool{false, true} {
+			_, err := jwk.DecodeKey(strings.NewReader(rsaPriMultiPrimeInvalidR), jwk.WithOptionDecodeKey(func(odk *jwk.OptionDecodeKey) {
+				odk.IgnoreValidate = ignore
+				odk.IgnorePrecomputed = ignore
+			}))
+			if !errors.Is(err, jwk.ErrInvalidOtherPrimes) {
+				t.Fatalf("expected %v is %v, but not", err, jwk.ErrInvalidOtherPrimes)
+			}
+			if !errors.Is(err, jwk.FieldError("r")) {
+				t.Fatalf("expected %v is %v, but not", err, jwk.FieldError("r"))
+			}
+		}
+	})
+	t.Run("oth not object", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(rsaPriMultiPrimeInvalidOth))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrInvalidArrayObject) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInvalidArrayObject)
+		}
+	})
 	t.Run("no precomputed", func(t *testing.T) {
 		_, err := jwk.DecodeKey(strings.NewReader(rsaPriNoPrecomputed))
 		if err == nil {
@@ -955,6 +1254,100 @@ func TestDecodeRSAPri(t *testing.T) {
 	})
 }
 
+func TestDecodeOKP(t *testing.T) {
+	t.Run("valid private", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(okpPriValid))
+		if err != nil {
+			t.Fatalf("expected value <nil>, but got %v", err)
+		}
+		if k.Kty() != jwk.KeyTypeOKP {
+			t.Fatalf("expected value %v, but got %v", jwk.KeyTypeOKP, k.Kty())
+		}
+		if _, ok := k.IntoPrivateKey().(ed25519.PrivateKey); !ok {
+			t.Fatalf("expected value %T, but got %T", ed25519.PrivateKey{}, k.IntoPrivateKey())
+		}
+	})
+	t.Run("valid public", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(okpPubValid))
+		if err != nil {
+			t.Fatalf("expected value <nil>, but got %v", err)
+		}
+		if k.Kty() != jwk.KeyTypeOKP {
+			t.Fatalf("expected value %v, but got %v", jwk.KeyTypeOKP, k.Kty())
+		}
+		if _, ok := k.IntoPublicKey().(ed25519.PublicKey); !ok {
+			t.Fatalf("expected value %T, but got %T", ed25519.PublicKey{}, k.IntoPublicKey())
+		}
+	})
+	withoutField("x", t, strings.NewReader(okpPriWithoutX))
+	t.Run("unknown crv", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(okpPubUnknownCrv))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseOKPPublicKey) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseOKPPublicKey)
+		}
+		if !errors.Is(err, jwk.ErrCauseUnknown) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseUnknown)
+		}
+	})
+	t.Run("invalid length x", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(okpPubInvalidLengthX))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrOKPInvalidBytesLength) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrOKPInvalidBytesLength)
+		}
+	})
+	t.Run("unmatched x", func(t *testing.T) {
+		_, err := jwk.DecodeKey(strings.NewReader(okpPriUnmatchedX))
+		if err == nil {
+			t.Fatalf("expected not <nil>, but got <nil>")
+		}
+		if !errors.Is(err, jwk.ErrCauseOKPValidate) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrCauseOKPValidate)
+		}
+		_, err = jwk.DecodeKey(strings.NewReader(okpPriUnmatchedX), jwk.WithOptionDecodeKey(func(odk *jwk.OptionDecodeKey) {
+			odk.IgnoreValidate = true
+		}))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("X25519", func(t *testing.T) {
+		// https://www.rfc-editor.org/rfc/rfc8037#appendix-A.6
+		prik, err := jwk.DecodeKey(strings.NewReader(okpX25519PriValid))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, ok := prik.(*jwk.ECDHPrivateKey); !ok {
+			t.Fatalf("expected value %T, but got %T", new(jwk.ECDHPrivateKey), prik)
+		}
+		pubk, err := jwk.DecodeKey(strings.NewReader(okpX25519PubValid))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, ok := pubk.(*jwk.ECDHPublicKey); !ok {
+			t.Fatalf("expected value %T, but got %T", new(jwk.ECDHPublicKey), pubk)
+		}
+		z, err := prik.IntoPrivateKey().(*ecdh.PrivateKey).ECDH(pubk.IntoPublicKey().(*ecdh.PublicKey))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if hex.EncodeToString(z) != "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742" {
+			t.Fatalf("unexpected shared secret %x", z)
+		}
+		if _, err := jwk.NewKey(pubk, jwk.AlgorithmECDHES_A128KW); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, err := jwk.NewKey(pubk, jwk.AlgorithmRSAOAEP); !errors.Is(err, jwk.ErrIncompatibleAlgorithm) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrIncompatibleAlgorithm)
+		}
+	})
+}
+
 func TestDecodeSet(t *testing.T) {
 	t.Run("invalid json", func(t *testing.T) {
 		_, err := jwk.DecodeSet(strings.NewReader(setInvalidJSON))
@@ -968,6 +1361,33 @@ func TestDecodeSet(t *testing.T) {
 			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInnerKey)
 		}
 	})
+	t.Run("small rsa key", func(t *testing.T) {
+		s, err := jwk.DecodeSet(strings.NewReader(`{"keys":[` + rsaPubSmallN + `,` + rsaPubValid + `]}`))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(s.Keys) != 2 {
+			t.Fatalf("expected 2 keys, but got %d", len(s.Keys))
+		}
+	})
+	t.Run("unsupported okp curve", func(t *testing.T) {
+		s, err := jwk.DecodeSet(strings.NewReader(setValidEd448))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, ok := s.GetKey("ed25519").(*jwk.OKPPublicKey); !ok {
+			t.Fatalf("expected %T, but got %T", new(jwk.OKPPublicKey), s.GetKey("ed25519"))
+		}
+		for _, kid := range []string{"ed448", "x448"} {
+			k, ok := s.GetKey(kid).(*jwk.UnknownKey)
+			if !ok {
+				t.Fatalf("expected %T, but got %T", new(jwk.UnknownKey), s.GetKey(kid))
+			}
+			if k.Kty() != jwk.KeyTypeOKP {
+				t.Fatalf("expected %v, but got %v", jwk.KeyTypeOKP, k.Kty())
+			}
+		}
+	})
 	t.Run("done context", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -2,10 +2,13 @@ package jwk
 
 import (
 	"context"
+	"crypto/ecdh"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
@@ -38,6 +41,20 @@ func EncodeKeyBy(ctx context.Context, src Key, dst io.Writer) error {
 		return ErrContextDone
 	default:
 	}
+	var option *OptionEncodeKey
+	MustGetOptionFromContext(ctx, &option, false)
+	data, err := encodeKeyBy(ctx, src, option.Canonical)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(dst, data, option.Canonical, option.Indent, !option.DisableHTMLEscape); err != nil {
+		return makeErrors(ErrInvalidJSON, err)
+	}
+	return nil
+}
+
+// encodeKeyBy make members of key, `canonical` is from option of key or set which is encoded
+func encodeKeyBy(ctx context.Context, src Key, canonical bool) (map[string]interface{}, error) {
 	var option *OptionEncodeKey
 	MustGetOptionFromContext(ctx, &option, false)
 	data := map[string]interface{}{"kty": src.Kty()}
@@ -45,7 +62,11 @@ func EncodeKeyBy(ctx context.Context, src Key, dst io.Writer) error {
 		data["use"] = src.Use()
 	}
 	if len(src.KeyOps()) > 0 {
-		data["key_ops"] = src.KeyOps().AsSlice()
+		order := option.KeyOpsOrder
+		if order == KeyOpsUnordered && canonical {
+			order = KeyOpsSorted
+		}
+		data["key_ops"] = keyOpsSlice(src.intoBaseKey(), order)
 	}
 	if src.Alg().Exist() {
 		data["alg"] = src.Alg()
@@ -63,6 +84,16 @@ func EncodeKeyBy(ctx context.Context, src Key, dst io.Writer) error {
 		}
 		data["x5c"] = certs
 	}
+	if len(src.X5c()) > 0 && option.ComputeThumbprint {
+		if len(src.X5t()) == 0 {
+			sum := sha1.Sum(src.X5c()[0].Raw)
+			data["x5t"] = base64.RawURLEncoding.EncodeToString(sum[:])
+		}
+		if len(src.X5tS256()) == 0 {
+			sum := sha256.Sum256(src.X5c()[0].Raw)
+			data["x5t#S256"] = base64.RawURLEncoding.EncodeToString(sum[:])
+		}
+	}
 	if len(src.X5t()) > 0 {
 		data["x5t"] = base64.RawURLEncoding.EncodeToString(src.X5t())
 	}
@@ -73,7 +104,7 @@ func EncodeKeyBy(ctx context.Context, src Key, dst io.Writer) error {
 	switch gokey := src.(type) {
 	case *RSAPrivateKey:
 		if err := encodePriRSA(data, gokey.Key); err != nil {
-			return err
+			return nil, err
 		}
 	case *RSAPublicKey:
 		encodePubRSA(data, gokey.Key)
@@ -83,6 +114,18 @@ func EncodeKeyBy(ctx context.Context, src Key, dst io.Writer) error {
 		encodePubEC(data, gokey.Key)
 	case *SymetricKey:
 		encodeSym(data, gokey.Key)
+	case *OKPPrivateKey:
+		encodePriOKP(data, gokey.Key)
+	case *OKPPublicKey:
+		encodePubOKP(data, gokey.Key)
+	case *ECDHPrivateKey:
+		encodePriECDH(data, gokey.Key)
+	case *ECDHPublicKey:
+		encodePubECDH(data, gokey.Key)
+	case *CustomKey:
+		if err := gokey.encodeMaterial(data); err != nil {
+			return nil, err
+		}
 	case *UnknownKey:
 	default:
 	}
@@ -91,10 +134,7 @@ func EncodeKeyBy(ctx context.Context, src Key, dst io.Writer) error {
 			data[k] = v
 		}
 	}
-	if err := json.NewEncoder(dst).Encode(data); err != nil {
-		return makeErrors(ErrInvalidJSON, err)
-	}
-	return nil
+	return data, nil
 }
 
 func encodePriRSA(data map[string]interface{}, prik *rsa.PrivateKey) error {
@@ -112,10 +152,30 @@ func encodePriRSA(data map[string]interface{}, prik *rsa.PrivateKey) error {
 	data["dp"] = base64.RawURLEncoding.EncodeToString(prik.Precomputed.Dp.Bytes())
 	data["dq"] = base64.RawURLEncoding.EncodeToString(prik.Precomputed.Dq.Bytes())
 	data["qi"] = base64.RawURLEncoding.EncodeToString(prik.Precomputed.Qinv.Bytes())
-	// TODO : data["oth"] fields
+	if len(prik.Primes) > 2 {
+		data["oth"] = encodeOthRSA(prik)
+	}
 	return nil
 }
 
+// `oth` is calculated from primes, not from `Precomputed.CRTValues`
+// https://www.rfc-editor.org/rfc/rfc7518.html#section-6.3.2.7
+func encodeOthRSA(prik *rsa.PrivateKey) []interface{} {
+	oth := make([]interface{}, 0, len(prik.Primes)-2)
+	r := new(big.Int).Mul(prik.Primes[0], prik.Primes[1])
+	for _, prime := range prik.Primes[2:] {
+		d := new(big.Int).Mod(prik.D, new(big.Int).Sub(prime, big.NewInt(1)))
+		t := new(big.Int).ModInverse(r, prime)
+		oth = append(oth, map[string]interface{}{
+			"r": base64.RawURLEncoding.EncodeToString(prime.Bytes()),
+			"d": base64.RawURLEncoding.EncodeToString(d.Bytes()),
+			"t": base64.RawURLEncoding.EncodeToString(t.Bytes()),
+		})
+		r.Mul(r, prime)
+	}
+	return oth
+}
+
 func encodePubRSA(data map[string]interface{}, pubk *rsa.PublicKey) {
 	data["n"] = base64.RawURLEncoding.EncodeToString(pubk.N.Bytes())
 	data["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pubk.E)).Bytes())
@@ -127,7 +187,7 @@ func encodePriEC(data map[string]interface{}, prik *ecdsa.PrivateKey) {
 }
 
 func encodePubEC(data map[string]interface{}, pubk *ecdsa.PublicKey) {
-	data["crv"] = pubk.Curve.Params().Name
+	data["crv"] = curveName(pubk.Curve)
 	data["x"] = safeECByte(pubk.Params().BitSize, pubk.X.Bytes())
 	data["y"] = safeECByte(pubk.Params().BitSize, pubk.Y.Bytes())
 }
@@ -149,6 +209,26 @@ func encodeSym(data map[string]interface{}, key []byte) {
 	data["k"] = base64.RawURLEncoding.EncodeToString(key)
 }
 
+func encodePriOKP(data map[string]interface{}, prik ed25519.PrivateKey) {
+	data["d"] = base64.RawURLEncoding.EncodeToString(prik.Seed())
+	encodePubOKP(data, prik.Public().(ed25519.PublicKey))
+}
+
+func encodePubOKP(data map[string]interface{}, pubk ed25519.PublicKey) {
+	data["crv"] = curveEd25519
+	data["x"] = base64.RawURLEncoding.EncodeToString(pubk)
+}
+
+func encodePriECDH(data map[string]interface{}, prik *ecdh.PrivateKey) {
+	data["d"] = base64.RawURLEncoding.EncodeToString(prik.Bytes())
+	encodePubECDH(data, prik.PublicKey())
+}
+
+func encodePubECDH(data map[string]interface{}, pubk *ecdh.PublicKey) {
+	data["crv"] = curveX25519
+	data["x"] = base64.RawURLEncoding.EncodeToString(pubk.Bytes())
+}
+
 func EncodeSet(src *Set, dst io.Writer, options ...OptionalEncodeSet) error {
 	ctx := context.Background()
 	for _, option := range options {
@@ -175,12 +255,33 @@ func EncodeSetBy(ctx context.Context, src *Set, dst io.Writer) error {
 	case <-ctx.Done():
 		return ErrContextDone
 	default:
-		err := json.NewEncoder(dst).Encode(map[string]interface{}{
-			"keys": src.Keys,
-		})
+	}
+	var option *OptionEncodeSet
+	MustGetOptionFromContext(ctx, &option, false)
+	keys := make([]interface{}, len(src.Keys))
+	for i, k := range src.Keys {
+		if k == nil {
+			// same as encoding/json, nil key is written as null
+			continue
+		}
+		data, err := encodeKeyBy(ctx, k, option.Canonical)
 		if err != nil {
-			return makeErrors(ErrInvalidJSON, err)
+			return makeErrors(ErrInnerKey, FieldError("keys"), IndexError(i), err)
+		}
+		if option.AssignKeyID != nil && len(k.Kid()) == 0 {
+			kid, err := option.AssignKeyID(k)
+			if err != nil {
+				return makeErrors(ErrInnerKey, FieldError("keys"), IndexError(i), FieldError("kid"), err)
+			}
+			data["kid"] = kid
 		}
-		return nil
+		keys[i] = data
 	}
+	err := writeJSON(dst, map[string]interface{}{
+		"keys": keys,
+	}, option.Canonical, option.Indent, !option.DisableHTMLEscape)
+	if err != nil {
+		return makeErrors(ErrInvalidJSON, err)
+	}
+	return nil
 }
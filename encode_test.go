@@ -25,15 +25,28 @@ var (
 	encECPri string
 	//go:embed embeding/ec-pub-valid.json
 	encECPub string
+	//go:embed embeding/ec-pri-valid-secp256k1.json
+	encECPriSecp256k1 string
 
 	//go:embed embeding/rsa-pri-valid.json
 	encRSAPri string
 	//go:embed embeding/rsa-pub-valid.json
 	encRSAPub string
+	//go:embed embeding/rsa-pri-valid-multi-prime.json
+	encRSAPriMultiPrime string
 
 	//go:embed embeding/octet-valid.json
 	encOctet string
 
+	//go:embed embeding/okp-pri-valid.json
+	encOKPPri string
+	//go:embed embeding/okp-pub-valid.json
+	encOKPPub string
+	//go:embed embeding/okp-x25519-pri-valid.json
+	encX25519Pri string
+	//go:embed embeding/okp-x25519-pub-valid.json
+	encX25519Pub string
+
 	//go:embed embeding/basekey-all.json
 	encBasekeyAll string
 )
@@ -170,6 +183,162 @@ func TestEncodeKey(t *testing.T) {
 		}
 	})
 
+	t.Run("ec secp256k1 private key", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(encECPriSecp256k1))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		kenc := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, kenc); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		var a map[string]interface{}
+		var b map[string]interface{}
+		if err := json.Unmarshal([]byte(encECPriSecp256k1), &a); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := json.Unmarshal(kenc.Bytes(), &b); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		if !reflect.DeepEqual(a, b) {
+			ja, _ := json.MarshalIndent(a, "", "    ")
+			jb, _ := json.MarshalIndent(b, "", "    ")
+			t.Fatalf("expected %v equal %v, but not", string(ja), string(jb))
+		}
+	})
+
+	t.Run("rsa multi-prime private key", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(encRSAPriMultiPrime))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		kenc := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, kenc); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		var a map[string]interface{}
+		var b map[string]interface{}
+		if err := json.Unmarshal([]byte(encRSAPriMultiPrime), &a); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := json.Unmarshal(kenc.Bytes(), &b); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		if !reflect.DeepEqual(a, b) {
+			ja, _ := json.MarshalIndent(a, "", "    ")
+			jb, _ := json.MarshalIndent(b, "", "    ")
+			t.Fatalf("expected %v equal %v, but not", string(ja), string(jb))
+		}
+	})
+
+	t.Run("okp private key", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(encOKPPri))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		kenc := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, kenc); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		var a map[string]interface{}
+		var b map[string]interface{}
+		if err := json.Unmarshal([]byte(encOKPPri), &a); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := json.Unmarshal(kenc.Bytes(), &b); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		if !reflect.DeepEqual(a, b) {
+			ja, _ := json.MarshalIndent(a, "", "    ")
+			jb, _ := json.MarshalIndent(b, "", "    ")
+			t.Fatalf("expected %v equal %v, but not", string(ja), string(jb))
+		}
+	})
+
+	t.Run("okp public key", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(encOKPPub))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		kenc := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, kenc); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		var a map[string]interface{}
+		var b map[string]interface{}
+		if err := json.Unmarshal([]byte(encOKPPub), &a); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := json.Unmarshal(kenc.Bytes(), &b); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		if !reflect.DeepEqual(a, b) {
+			ja, _ := json.MarshalIndent(a, "", "    ")
+			jb, _ := json.MarshalIndent(b, "", "    ")
+			t.Fatalf("expected %v equal %v, but not", string(ja), string(jb))
+		}
+	})
+
+	t.Run("x25519 private key", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(encX25519Pri))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		kenc := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, kenc); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		var a map[string]interface{}
+		var b map[string]interface{}
+		if err := json.Unmarshal([]byte(encX25519Pri), &a); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := json.Unmarshal(kenc.Bytes(), &b); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		if !reflect.DeepEqual(a, b) {
+			ja, _ := json.MarshalIndent(a, "", "    ")
+			jb, _ := json.MarshalIndent(b, "", "    ")
+			t.Fatalf("expected %v equal %v, but not", string(ja), string(jb))
+		}
+	})
+
+	t.Run("x25519 public key", func(t *testing.T) {
+		k, err := jwk.DecodeKey(strings.NewReader(encX25519Pub))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		kenc := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, kenc); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		var a map[string]interface{}
+		var b map[string]interface{}
+		if err := json.Unmarshal([]byte(encX25519Pub), &a); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := json.Unmarshal(kenc.Bytes(), &b); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		//
+		if !reflect.DeepEqual(a, b) {
+			ja, _ := json.MarshalIndent(a, "", "    ")
+			jb, _ := json.MarshalIndent(b, "", "    ")
+			t.Fatalf("expected %v equal %v, but not", string(ja), string(jb))
+		}
+	})
+
 	t.Run("basekey", func(t *testing.T) {
 		bcert, err := base64.RawStdEncoding.DecodeString(`MIIDazCCAlOgAwIBAgIUKAvNNGGWUrUKgLYZD3d+hpbBoT0wDQYJKoZIhvcNAQELBQAwRTELMAkGA1UEBhMCS1IxEzARBgNVBAgMClNvbWUtU3RhdGUxITAfBgNVBAoMGEludGVybmV0IFdpZGdpdHMgUHR5IEx0ZDAeFw0yMjAxMDYxMzE3MjBaFw0zMjAxMDQxMzE3MjBaMEUxCzAJBgNVBAYTAktSMRMwEQYDVQQIDApTb21lLVN0YXRlMSEwHwYDVQQKDBhJbnRlcm5ldCBXaWRnaXRzIFB0eSBMdGQwggEiMA0GCSqGSIb3DQEBAQUAA4IBDwAwggEKAoIBAQDXE/RPj9ej1gPtqBBMiN7XCqGdrXaw3ZKIAFHT8NIEJ7DklioJ/Nve+Fqp5yIbspoC8HGs8zxgIAGLyRY7WejLkZyplpTpA4PAHmK9ZRClbYFNoTaz733FNc/hqPMuMpwb1FPgR832lj/mEgxtMIaxrN3ZFlmknnWck9z+GEb4JA0AQOwpj85Eakc9EqTwSn7thgsQqPAT3ywX14kDVnSU+z2qLjmr6ocV78RPDaBgPcK/uzYu6VtPtlML2im3iijmHD8Z2LXOQwauX549A9icO/E02qyAz85/cDka8iEcUbwXEbRVnclii8LpfXIUKNZcCh6Cjr1FRIet+iNpyT9XAgMBAAGjUzBRMB0GA1UdDgQWBBTyRsAwPim3sjoo0qKSaUnfugmaXzAfBgNVHSMEGDAWgBTyRsAwPim3sjoo0qKSaUnfugmaXzAPBgNVHRMBAf8EBTADAQH/MA0GCSqGSIb3DQEBCwUAA4IBAQB+01IAp57m5oDB9RYB69qj6Isopd3AI2sh/NvAN+F8CQafPexq4eBo+iXvhMS5yu3NEn3wPzrX6RBGYnjq784jDp4nDOvNd9kE/6aj2IG5RM6tcilvhamy5/6d4cLE0Rg6rco6bEeLtu7IKKFZpW72STP3a36munv6dopZYtCeXTYQE8t0MKjBCcIksXHthnTfzOT8EhCAp1pYX23nq2sPfQNaNTYcQcVhyNqkyviPcrvnJnZUavzngMGajy+io2kRfLmPdzPUMmkfgacXxjsl5hI3jecmKzTTR3gOZvdgIgV2DJyYEs9/dKXIHL7o6D4j7cnTqRtQwoPlQEuuOJVD`)
 		if err != nil {
@@ -179,12 +348,12 @@ func TestEncodeKey(t *testing.T) {
 		if err != nil {
 			t.Fatalf("expected <nil>, but got %v", err)
 		}
-		symk, err := base64.RawURLEncoding.DecodeString("GawgguFyGrWKav7AX4VKUg")
+		symk, err := base64.RawURLEncoding.DecodeString("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
 		if err != nil {
 			t.Fatalf("expected <nil>, but got %v", err)
 		}
-		th, _ := base64.RawURLEncoding.DecodeString("bm90aGluZ3NwZWNpYWxpbmhlcmU")
-		th256, _ := base64.RawURLEncoding.DecodeString("bm90aGluZ3NwZWNpYWxpbmhlcmVyZWFsbHlub3RoaW4")
+		th, _ := base64.RawURLEncoding.DecodeString("03jgiB63g0Kfw50QFV0h0zufI8M")
+		th256, _ := base64.RawURLEncoding.DecodeString("eAWMaO__zmZ3mWQex1j0jBeKZeigBSYccXxmn00RL_E")
 		k := &jwk.SymetricKey{
 			BaseKey: jwk.BaseKey{
 				KeyUse: jwk.KeyUseSig,
@@ -242,6 +411,32 @@ func TestEncodeKey(t *testing.T) {
 
 	})
 
+	t.Run("compute thumbprint", func(t *testing.T) {
+		k, err := jwk.ParsePEM(pemECChain)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		x5t, x5tS256 := k.X5t(), k.X5tS256()
+		k.(*jwk.ECPrivateKey).X509CertThumbprint = nil
+		k.(*jwk.ECPrivateKey).X509CertThumbprintS256 = nil
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, buf, jwk.WithOptionEncodeKey(func(value *jwk.OptionEncodeKey) { value.ComputeThumbprint = true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		dk, err := jwk.DecodeKey(buf)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if !bytes.Equal(dk.X5t(), x5t) || !bytes.Equal(dk.X5tS256(), x5tS256) {
+			t.Fatalf("expected %x, %x, but got %x, %x", x5t, x5tS256, dk.X5t(), dk.X5tS256())
+		}
+		buf.Reset()
+		jwk.MustEncodeKey(k, buf)
+		if strings.Contains(buf.String(), "x5t") {
+			t.Fatalf("expected no thumbprint without option, but got %s", buf.String())
+		}
+	})
+
 	t.Run("done context", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -377,6 +572,42 @@ func TestEncodeSet(t *testing.T) {
 		}
 	})
 
+	t.Run("assign kid", func(t *testing.T) {
+		eck0, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		eck1, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		k0 := jwk.MustKey(eck0, jwk.WithKeyID("fixed"))
+		k1 := jwk.MustKey(eck1)
+		s := jwk.NewSet(k0, k1)
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeSet(s, buf, jwk.WithKeyIDFunc(jwk.KeyIDThumbprint)); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k1.Kid() != "" {
+			t.Fatalf("expected key in set unchanged, but got kid %v", k1.Kid())
+		}
+		sb, err := jwk.DecodeSet(buf)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		expected, _ := jwk.KeyIDThumbprint(k1)
+		if sb.Keys[0].Kid() != "fixed" {
+			t.Fatalf("expected %v, but got %v", "fixed", sb.Keys[0].Kid())
+		}
+		if sb.Keys[1].Kid() != expected {
+			t.Fatalf("expected %v, but got %v", expected, sb.Keys[1].Kid())
+		}
+	})
+
+	t.Run("assign kid error", func(t *testing.T) {
+		eck, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		s := jwk.NewSet(jwk.MustKey(eck))
+		expected := errors.New("no kid")
+		err := jwk.EncodeSet(s, bytes.NewBuffer(nil), jwk.WithKeyIDFunc(func(k jwk.Key) (string, error) { return "", expected }))
+		if !errors.Is(err, jwk.ErrInnerKey) || !errors.Is(err, expected) {
+			t.Fatalf("expected %v, but got %v", expected, err)
+		}
+	})
+
 	t.Run("done context", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -408,3 +639,91 @@ func TestEncodeSet(t *testing.T) {
 		}
 	})
 }
+
+func TestEncodeCanonical(t *testing.T) {
+	// members from https://www.rfc-editor.org/rfc/rfc8785#section-3.2.3
+	const src = `{"kty":"oct","key_ops":["verify","sign"],"kid":"<a&b>","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8","num":[1e-7,1.5,100,1e21,-0],"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7}`
+	allow := jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.AllowUnknownField = true })
+	encode := func(t *testing.T, k jwk.Key, handle func(value *jwk.OptionEncodeKey)) string {
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeKey(k, buf, jwk.WithOptionEncodeKey(handle)); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		return buf.String()
+	}
+	t.Run("canonical", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(src), allow)
+		expected := "{\"\\r\":2,\"1\":4," +
+			"\"k\":\"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8\",\"key_ops\":[\"sign\",\"verify\"],\"kid\":\"<a&b>\",\"kty\":\"oct\"," +
+			"\"num\":[1e-7,1.5,100,1e+21,0]," +
+			"\"\u0080\":6,\"\u00f6\":7,\"\u20ac\":1,\"\U0001f600\":5,\"\ufb33\":3}\n"
+		for i := 0; i < 10; i++ {
+			if got := encode(t, k, func(value *jwk.OptionEncodeKey) { value.Canonical = true }); got != expected {
+				t.Fatalf("expected %q, but got %q", expected, got)
+			}
+		}
+	})
+	t.Run("declared key_ops", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(src), allow)
+		got := encode(t, k, func(value *jwk.OptionEncodeKey) {
+			value.Canonical = true
+			value.KeyOpsOrder = jwk.KeyOpsDeclared
+		})
+		if !strings.Contains(got, `"key_ops":["verify","sign"]`) {
+			t.Fatalf("expected declared order, but got %s", got)
+		}
+		pubk := k.Clone()
+		delete(pubk.KeyOps(), jwk.KeyOpSign)
+		pubk.KeyOps()[jwk.KeyOpEncrypt] = struct{}{}
+		got = encode(t, pubk, func(value *jwk.OptionEncodeKey) { value.KeyOpsOrder = jwk.KeyOpsDeclared })
+		if !strings.Contains(got, `"key_ops":["verify","encrypt"]`) {
+			t.Fatalf("expected declared order then added op, but got %s", got)
+		}
+	})
+	t.Run("sorted key_ops", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(src), allow)
+		got := encode(t, k, func(value *jwk.OptionEncodeKey) { value.KeyOpsOrder = jwk.KeyOpsSorted })
+		if !strings.Contains(got, `"key_ops":["sign","verify"]`) {
+			t.Fatalf("expected sorted order, but got %s", got)
+		}
+	})
+	t.Run("html escape", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(src), allow)
+		if got := encode(t, k, func(value *jwk.OptionEncodeKey) {}); !strings.Contains(got, `"kid":"\u003ca\u0026b\u003e"`) {
+			t.Fatalf("expected escaped kid, but got %s", got)
+		}
+		if got := encode(t, k, func(value *jwk.OptionEncodeKey) { value.DisableHTMLEscape = true }); !strings.Contains(got, `"kid":"<a&b>"`) {
+			t.Fatalf("expected not escaped kid, but got %s", got)
+		}
+	})
+	t.Run("indent", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(`{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`))
+		expected := "{\n  \"k\": \"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8\",\n  \"kty\": \"oct\"\n}\n"
+		if got := encode(t, k, func(value *jwk.OptionEncodeKey) { value.Canonical, value.Indent = true, "  " }); got != expected {
+			t.Fatalf("expected %q, but got %q", expected, got)
+		}
+	})
+	t.Run("nil key", func(t *testing.T) {
+		s := &jwk.Set{Keys: []jwk.Key{nil}}
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeSet(s, buf, jwk.WithOptionEncodeSet(func(value *jwk.OptionEncodeSet) { value.Canonical = true })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if expected := `{"keys":[null]}` + "\n"; buf.String() != expected {
+			t.Fatalf("expected %q, but got %q", expected, buf.String())
+		}
+	})
+	t.Run("set", func(t *testing.T) {
+		s := &jwk.Set{Keys: []jwk.Key{jwk.MustDecodeKey(strings.NewReader(`{"kty":"oct","key_ops":["verify","sign"],"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`))}}
+		expected := `{"keys":[{"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8","key_ops":["sign","verify"],"kty":"oct"}]}` + "\n"
+		for i := 0; i < 10; i++ {
+			buf := bytes.NewBuffer(nil)
+			if err := jwk.EncodeSet(s, buf, jwk.WithOptionEncodeSet(func(value *jwk.OptionEncodeSet) { value.Canonical = true })); err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			if buf.String() != expected {
+				t.Fatalf("expected %q, but got %q", expected, buf.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/subtle"
+	"reflect"
+)
+
+type EqualMode uint8
+
+const (
+	// EqualMaterial compare only cryptographic material, private key is never equal with public key
+	EqualMaterial EqualMode = iota
+	// EqualFull compare cryptographic material and every member, including extra fields
+	EqualFull
+)
+
+func (key *UnknownKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *RSAPrivateKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *RSAPublicKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *ECPrivateKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *ECPublicKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *SymetricKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *OKPPrivateKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *OKPPublicKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *ECDHPrivateKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+func (key *ECDHPublicKey) Equal(other Key, mode EqualMode) bool {
+	return equalKey(key, other, mode)
+}
+
+// Contains report whether set has key which is equal with key
+func (set *Set) Contains(key Key, mode EqualMode) bool {
+	if key == nil {
+		return false
+	}
+	for _, k := range set.Keys {
+		if k != nil && k.Equal(key, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalKey(a Key, b Key, mode EqualMode) bool {
+	if b == nil || a.Kty() != b.Kty() {
+		return false
+	}
+	if !equalMaterial(a, b) {
+		return false
+	}
+	if mode == EqualFull {
+		return equalMembers(a, b)
+	}
+	return true
+}
+
+func equalMaterial(a Key, b Key) bool {
+	if ua, ok := a.(*UnknownKey); ok {
+		ub, ok := b.(*UnknownKey)
+		return ok && equalExtra(ua.Extra(), ub.Extra())
+	}
+	if ca, ok := a.(*CustomKey); ok {
+		return equalCustomMaterial(ca, b)
+	}
+	if a.Kty() == KeyTypeOctet {
+		ka, oka := a.IntoKey().([]byte)
+		kb, okb := b.IntoKey().([]byte)
+		return oka && okb && subtle.ConstantTimeCompare(ka, kb) == 1
+	}
+	pria, prib := a.IntoPrivateKey(), b.IntoPrivateKey()
+	switch {
+	case pria != nil && prib != nil:
+		eq, ok := pria.(interface{ Equal(crypto.PrivateKey) bool })
+		return ok && eq.Equal(prib)
+	case pria == nil && prib == nil:
+		eq, ok := a.IntoPublicKey().(interface{ Equal(crypto.PublicKey) bool })
+		return ok && eq.Equal(b.IntoPublicKey())
+	default:
+		return false
+	}
+}
+
+func equalMembers(a Key, b Key) bool {
+	if a.Use() != b.Use() || a.Alg() != b.Alg() || a.Kid() != b.Kid() {
+		return false
+	}
+	if len(a.KeyOps()) != len(b.KeyOps()) || !b.KeyOps().All(a.KeyOps().AsSlice()...) {
+		return false
+	}
+	if (a.X5u() == nil) != (b.X5u() == nil) || a.X5u() != nil && a.X5u().String() != b.X5u().String() {
+		return false
+	}
+	if len(a.X5c()) != len(b.X5c()) {
+		return false
+	}
+	for i := range a.X5c() {
+		if !a.X5c()[i].Equal(b.X5c()[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(a.X5t(), b.X5t()) || !bytes.Equal(a.X5tS256(), b.X5tS256()) {
+		return false
+	}
+	if _, ok := a.(*UnknownKey); ok {
+		// extra of unknown key is already compared as material
+		return true
+	}
+	return equalExtra(a.Extra(), b.Extra())
+}
+
+func equalExtra(a map[string]interface{}, b map[string]interface{}) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
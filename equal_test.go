@@ -0,0 +1,83 @@
+package jwk_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestEqual(t *testing.T) {
+	srcs := []string{encRSAPri, encRSAPub, encECPri, encECPub, encOKPPri, encOKPPub, encX25519Pri, encX25519Pub, encOctet, encBasekeyAll}
+	t.Run("same", func(t *testing.T) {
+		for _, src := range srcs {
+			a := jwk.MustDecodeKey(strings.NewReader(src))
+			b := jwk.MustDecodeKey(strings.NewReader(src))
+			if !a.Equal(b, jwk.EqualMaterial) || !a.Equal(b, jwk.EqualFull) {
+				t.Fatalf("expected equal, but not for %s", src)
+			}
+		}
+	})
+	t.Run("different metadata", func(t *testing.T) {
+		for _, src := range srcs {
+			a := jwk.MustDecodeKey(strings.NewReader(src))
+			b := jwk.MustKey(a.Clone(), jwk.WithKeyID("other"))
+			if !a.Equal(b, jwk.EqualMaterial) {
+				t.Fatalf("expected same material, but not for %s", src)
+			}
+			if a.Equal(b, jwk.EqualFull) {
+				t.Fatalf("expected different, but equal for %s", src)
+			}
+		}
+	})
+	t.Run("different extra", func(t *testing.T) {
+		a := jwk.MustDecodeKey(strings.NewReader(encECPub))
+		b := a.Clone()
+		b.Extra()["custom"] = "value"
+		if !a.Equal(b, jwk.EqualMaterial) || a.Equal(b, jwk.EqualFull) {
+			t.Fatalf("expected only material equal, but not")
+		}
+	})
+	t.Run("different material", func(t *testing.T) {
+		for i, a := range srcs[:len(srcs)-1] {
+			for j, b := range srcs[:len(srcs)-1] {
+				if i == j {
+					continue
+				}
+				if jwk.MustDecodeKey(strings.NewReader(a)).Equal(jwk.MustDecodeKey(strings.NewReader(b)), jwk.EqualMaterial) {
+					t.Fatalf("expected different, but equal for %s and %s", a, b)
+				}
+			}
+		}
+	})
+	t.Run("symetric key", func(t *testing.T) {
+		if !jwk.MustKey([]byte("secret")).Equal(jwk.MustKey("secret"), jwk.EqualMaterial) {
+			t.Fatalf("expected equal, but not")
+		}
+		if jwk.MustKey([]byte("secret")).Equal(jwk.MustKey("secreT"), jwk.EqualMaterial) {
+			t.Fatalf("expected different, but equal")
+		}
+	})
+	t.Run("nil", func(t *testing.T) {
+		if jwk.MustDecodeKey(strings.NewReader(encECPub)).Equal(nil, jwk.EqualMaterial) {
+			t.Fatalf("expected different, but equal")
+		}
+	})
+}
+
+func TestSetContains(t *testing.T) {
+	s := jwk.NewSet(jwk.MustDecodeKey(strings.NewReader(encRSAPub)), jwk.MustDecodeKey(strings.NewReader(encECPub)))
+	fetched := jwk.MustKey(jwk.MustDecodeKey(strings.NewReader(encECPub)), jwk.WithKeyID("rotated"))
+	if !s.Contains(fetched, jwk.EqualMaterial) {
+		t.Fatalf("expected contains, but not")
+	}
+	if s.Contains(fetched, jwk.EqualFull) {
+		t.Fatalf("expected not contains, but contains")
+	}
+	if s.Contains(jwk.MustDecodeKey(strings.NewReader(encECPri)), jwk.EqualMaterial) {
+		t.Fatalf("expected private key not contains, but contains")
+	}
+	if s.Contains(nil, jwk.EqualMaterial) {
+		t.Fatalf("expected not contains, but contains")
+	}
+}
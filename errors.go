@@ -19,24 +19,36 @@ var (
 	ErrInvalidObject         = errors.New("invalid object")
 	ErrInvalidURL            = errors.New("invalid url")
 	ErrInvalidX509           = errors.New("invalid x509")
+	ErrInvalidPEM            = errors.New("invalid pem")
 	ErrInvalidJSON           = errors.New("invalid json")
 	ErrInvalidBase64         = errors.New("invalid base64")
+	ErrInvalidThumbprintURI  = errors.New("invalid thumbprint uri")
 	ErrUnknownKeyUse         = errors.New("unknown use")
 	ErrIncompatibleAlgorithm = errors.New("imcompatible algorithm")
 	ErrIncompatibleType      = errors.New("imcompatible type")
+	ErrUnsupportedKeyType    = errors.New("unsupported key type")
+	ErrUnavailableHash       = errors.New("unavailable hash")
+	ErrUnsupportedAlgorithm  = errors.New("unsupported algorithm")
 )
 var (
-	ErrCauseOption        = errors.New("cause option")
-	ErrCauseUnknown       = errors.New("unknown")
-	ErrCauseECPublicKey   = errors.New("ec public key failed")
-	ErrCauseECPrivateKey  = errors.New("ec private key failed")
-	ErrCauseRSAPublicKey  = errors.New("rsa public key failed")
-	ErrCauseRSAPrivateKey = errors.New("rsa private key failed")
-	ErrCauseRSAValidate   = errors.New("rsa validate fail")
-	ErrCauseSymetricKey   = errors.New("symetric key failed")
+	ErrCauseOption           = errors.New("cause option")
+	ErrCauseUnknown          = errors.New("unknown")
+	ErrCauseECPublicKey      = errors.New("ec public key failed")
+	ErrCauseECPrivateKey     = errors.New("ec private key failed")
+	ErrCauseRSAPublicKey     = errors.New("rsa public key failed")
+	ErrCauseRSAPrivateKey    = errors.New("rsa private key failed")
+	ErrCauseRSAValidate      = errors.New("rsa validate fail")
+	ErrCauseECValidate       = errors.New("ec validate fail")
+	ErrCauseSymetricKey      = errors.New("symetric key failed")
+	ErrCauseSymetricValidate = errors.New("symetric validate fail")
+	ErrCauseOKPPublicKey     = errors.New("okp public key failed")
+	ErrCauseOKPPrivateKey    = errors.New("okp private key failed")
+	ErrCauseOKPValidate      = errors.New("okp validate fail")
+	ErrCauseCustomKey        = errors.New("custom key failed")
 )
 var (
 	ErrECInvalidBytesLength     = errors.New("invalid byte length")
+	ErrOKPInvalidBytesLength    = errors.New("invalid okp byte length")
 	ErrNoSelectedKey            = errors.New("no selected key")
 	ErrNotExpectedKty           = errors.New("not expected kty")
 	ErrNotCompatible            = errors.New("not compatible")
@@ -48,6 +60,16 @@ var (
 	ErrDisallowUnknownOp        = errors.New("disallow unknown op")
 	ErrDisallowUnknownAlgorithm = errors.New("disallow unknown algorithm")
 	ErrDisallowDuplicatedOps    = errors.New("disallow duplicated ops")
+	ErrInvalidOtherPrimes       = errors.New("invalid other primes info")
+	ErrInsufficientKeySize      = errors.New("insufficient key size")
+	ErrX5cKeyMismatch           = errors.New("leaf certificate key mismatch")
+	ErrX5cBrokenChain           = errors.New("certificate not signed by next")
+	ErrX5cUntrusted             = errors.New("certificate chain untrusted")
+	ErrX5cExpired               = errors.New("certificate expired or not yet valid")
+	ErrX5cKeyUsage              = errors.New("certificate key usage not allowed")
+	ErrX5tMismatch              = errors.New("thumbprint mismatch with leaf certificate")
+	ErrX5uTooLarge              = errors.New("x5u response too large")
+	ErrForbiddenOperation       = errors.New("forbidden operation")
 )
 
 type (
@@ -57,6 +79,13 @@ type (
 	}
 	FieldError string
 	IndexError int
+	// OperationError is returned when key is used for operation which is not permitted by `use` or `key_ops`
+	// It is `ErrForbiddenOperation`
+	OperationError struct {
+		Op     KeyOp
+		Use    KeyUse
+		KeyOps []KeyOp
+	}
 )
 
 func makeErrors(err ...error) error {
@@ -85,6 +114,13 @@ func (fe FieldError) Error() string {
 	return fmt.Sprintf("'%s'", string(fe))
 }
 
+func (oe *OperationError) Error() string {
+	return fmt.Sprintf("%v, '%s' is not permitted by use='%s', key_ops=%v", ErrForbiddenOperation, oe.Op, oe.Use, oe.KeyOps)
+}
+func (oe *OperationError) Is(other error) bool {
+	return other == ErrForbiddenOperation
+}
+
 func (we *wrapError) Error() string {
 	if we.child == nil {
 		return fmt.Sprintf("%v", we.current)
@@ -0,0 +1,155 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+)
+
+type (
+	OptionGenerateKey struct {
+		// Random is source of randomness, default is `crypto/rand.Reader`
+		Random io.Reader
+		// RSABits is size of RSA modulus, default is 2048, and it must be 2048 or larger
+		RSABits int
+		// KeyUse is `use` of key, if both `KeyUse` and `KeyOps` are empty, `use` is decided by algorithm
+		KeyUse KeyUse
+		// KeyOps is `key_ops` of key
+		KeyOps KeyOps
+		// KeyID make `kid` of key, default is `KeyIDThumbprint`
+		KeyID func(Key) (string, error)
+	}
+	OptionalGenerateKey interface {
+		WithGenerateKey(*OptionGenerateKey)
+	}
+	withRandom  struct{ reader io.Reader }
+	WithRSABits int
+	WithKeyUse  KeyUse
+	WithKeyOps  []KeyOp
+)
+
+// WithRandom set source of randomness, it is useful for deterministic test
+// Allowed for
+//
+//	`OptionalGenerateKey`
+func WithRandom(reader io.Reader) *withRandom {
+	return &withRandom{reader: reader}
+}
+
+func (w *withRandom) WithGenerateKey(o *OptionGenerateKey) { o.Random = w.reader }
+func (w WithRSABits) WithGenerateKey(o *OptionGenerateKey) { o.RSABits = int(w) }
+func (w WithKeyUse) WithGenerateKey(o *OptionGenerateKey)  { o.KeyUse = KeyUse(w) }
+func (w WithKeyOps) WithGenerateKey(o *OptionGenerateKey) {
+	o.KeyOps = make(KeyOps, len(w))
+	for _, op := range w {
+		o.KeyOps[op] = struct{}{}
+	}
+}
+func (w WithKeyID) WithGenerateKey(o *OptionGenerateKey) {
+	o.KeyID = func(Key) (string, error) { return string(w), nil }
+}
+func (w WithKeyIDFunc) WithGenerateKey(o *OptionGenerateKey) { o.KeyID = w }
+
+// GenerateKey make new random key for algorithm
+//   - HS256, HS384, HS512                                -> *SymetricKey, 32, 48, 64 bytes
+//   - A128KW, A192KW, A256KW, A128GCMKW, A192GCMKW,
+//     A256GCMKW, A128GCM, A192GCM, A256GCM               -> *SymetricKey, 16, 24, 32 bytes
+//   - A128CBC-HS256, A192CBC-HS384, A256CBC-HS512        -> *SymetricKey, 32, 48, 64 bytes
+//   - RS*, PS*, RSA1_5, RSA-OAEP, RSA-OAEP-256           -> *RSAPrivateKey
+//   - ES256, ES384, ES512, ES256K, registered curve alg  -> *ECPrivateKey
+//   - ECDH-ES, ECDH-ES+A*KW                              -> *ECPrivateKey, P-256
+//   - EdDSA                                              -> *OKPPrivateKey, Ed25519
+//
+// `alg`, `use` and `kid` are set, `kid` is RFC 7638 thumbprint if not specified
+func GenerateKey(alg Algorithm, options ...OptionalGenerateKey) (Key, error) {
+	option := &OptionGenerateKey{
+		Random:  rand.Reader,
+		RSABits: 2048,
+		KeyID:   KeyIDThumbprint,
+	}
+	for _, o := range options {
+		o.WithGenerateKey(option)
+	}
+	var (
+		key Key
+		err error
+	)
+	switch alg {
+	case AlgorithmHS256, AlgorithmHS384, AlgorithmHS512,
+		AlgorithmA128KW, AlgorithmA192KW, AlgorithmA256KW,
+		AlgorithmA128GCMKW, AlgorithmA192GCMKW, AlgorithmA256GCMKW,
+		AlgorithmA128GCM, AlgorithmA192GCM, AlgorithmA256GCM,
+		AlgorithmA128CBC_HS256, AlgorithmA192CBC_HS384, AlgorithmA256CBC_HS512:
+		size, _ := symetricKeySize(alg)
+		key, err = generateSymetricKey(option.Random, size)
+	case AlgorithmRS256, AlgorithmRS384, AlgorithmRS512, AlgorithmPS256, AlgorithmPS384, AlgorithmPS512,
+		AlgorithmRSA1_5, AlgorithmRSAOAEP, AlgorithmRSAOAEP256:
+		key, err = generateRSAKey(option.Random, option.RSABits)
+	case AlgorithmECDHES, AlgorithmECDHES_A128KW, AlgorithmECDHES_A192KW, AlgorithmECDHES_A256KW:
+		key, err = generateECKey(option.Random, elliptic.P256())
+	case AlgorithmEdDSA:
+		var prik ed25519.PrivateKey
+		if _, prik, err = ed25519.GenerateKey(option.Random); err == nil {
+			key, err = NewKey(prik)
+		}
+	default:
+		e := lookupCurveByAlgorithm(alg)
+		if e == nil {
+			return nil, makeErrors(ErrUnsupportedAlgorithm, fmt.Errorf("can't generate key for '%s'", alg))
+		}
+		key, err = generateECKey(option.Random, e.curve)
+	}
+	if err != nil {
+		return nil, err
+	}
+	bk := key.intoBaseKey()
+	bk.Algorithm = alg
+	switch {
+	case option.KeyUse.Exist() || len(option.KeyOps) > 0:
+		bk.KeyUse = option.KeyUse
+		for op := range option.KeyOps {
+			bk.KeyOperations[op] = struct{}{}
+		}
+	default:
+		if info, ok := alg.Info(); ok {
+			bk.KeyUse = info.KeyUse
+		}
+	}
+	if option.KeyID != nil {
+		if bk.KeyID, err = option.KeyID(key); err != nil {
+			return nil, makeErrors(FieldError("kid"), err)
+		}
+	}
+	return key, nil
+}
+
+func generateSymetricKey(random io.Reader, size int) (Key, error) {
+	k := make([]byte, size)
+	if _, err := io.ReadFull(random, k); err != nil {
+		return nil, err
+	}
+	return NewKey(k)
+}
+
+func generateRSAKey(random io.Reader, bits int) (Key, error) {
+	if bits < 2048 {
+		return nil, makeErrors(ErrInsufficientKeySize, fmt.Errorf("rsa modulus must be 2048 bits or larger, but got %d", bits))
+	}
+	prik, err := rsa.GenerateKey(random, bits)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(prik)
+}
+
+func generateECKey(random io.Reader, curve elliptic.Curve) (Key, error) {
+	prik, err := ecdsa.GenerateKey(curve, random)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(prik)
+}
@@ -0,0 +1,146 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestGenerateKey(t *testing.T) {
+	t.Run("key type and size", func(t *testing.T) {
+		for _, tc := range []struct {
+			alg  jwk.Algorithm
+			kty  jwk.KeyType
+			use  jwk.KeyUse
+			size int
+		}{
+			{jwk.AlgorithmHS256, jwk.KeyTypeOctet, jwk.KeyUseSig, 32},
+			{jwk.AlgorithmHS384, jwk.KeyTypeOctet, jwk.KeyUseSig, 48},
+			{jwk.AlgorithmHS512, jwk.KeyTypeOctet, jwk.KeyUseSig, 64},
+			{jwk.AlgorithmA128KW, jwk.KeyTypeOctet, jwk.KeyUseEnc, 16},
+			{jwk.AlgorithmA192GCMKW, jwk.KeyTypeOctet, jwk.KeyUseEnc, 24},
+			{jwk.AlgorithmA256GCM, jwk.KeyTypeOctet, jwk.KeyUseEnc, 32},
+			{jwk.AlgorithmA256CBC_HS512, jwk.KeyTypeOctet, jwk.KeyUseEnc, 64},
+			{jwk.AlgorithmRS256, jwk.KeyTypeRSA, jwk.KeyUseSig, 256},
+			{jwk.AlgorithmRSAOAEP, jwk.KeyTypeRSA, jwk.KeyUseEnc, 256},
+			{jwk.AlgorithmES256, jwk.KeyTypeEC, jwk.KeyUseSig, 32},
+			{jwk.AlgorithmES384, jwk.KeyTypeEC, jwk.KeyUseSig, 48},
+			{jwk.AlgorithmES512, jwk.KeyTypeEC, jwk.KeyUseSig, 66},
+			{jwk.AlgorithmES256K, jwk.KeyTypeEC, jwk.KeyUseSig, 32},
+			{jwk.AlgorithmECDHES, jwk.KeyTypeEC, jwk.KeyUseEnc, 32},
+			{jwk.AlgorithmEdDSA, jwk.KeyTypeOKP, jwk.KeyUseSig, ed25519.PrivateKeySize},
+		} {
+			k, err := jwk.GenerateKey(tc.alg)
+			if err != nil {
+				t.Fatalf("%s : expected <nil>, but got %v", tc.alg, err)
+			}
+			if k.Kty() != tc.kty || k.Alg() != tc.alg || k.Use() != tc.use {
+				t.Fatalf("%s : expected (%s, %s, %s), but got (%s, %s, %s)", tc.alg, tc.kty, tc.alg, tc.use, k.Kty(), k.Alg(), k.Use())
+			}
+			var size int
+			switch gokey := k.IntoKey().(type) {
+			case []byte:
+				size = len(gokey)
+			case *rsa.PrivateKey:
+				size = gokey.Size()
+			case *ecdsa.PrivateKey:
+				size = (gokey.Params().BitSize + 7) / 8
+			case ed25519.PrivateKey:
+				size = len(gokey)
+			}
+			if size != tc.size {
+				t.Fatalf("%s : expected size %d, but got %d", tc.alg, tc.size, size)
+			}
+			kid, _ := jwk.KeyIDThumbprint(k)
+			if k.Kid() != kid {
+				t.Fatalf("%s : expected kid %s, but got %s", tc.alg, kid, k.Kid())
+			}
+		}
+	})
+	t.Run("deterministic random", func(t *testing.T) {
+		seed := bytes.Repeat([]byte{0x42}, 1024)
+		k0, err := jwk.GenerateKey(jwk.AlgorithmES256, jwk.WithRandom(bytes.NewReader(seed)))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		k1, err := jwk.GenerateKey(jwk.AlgorithmES256, jwk.WithRandom(bytes.NewReader(seed)))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k0.Kid() != k1.Kid() {
+			t.Fatalf("expected same key, but got %s, %s", k0.Kid(), k1.Kid())
+		}
+		k2, err := jwk.GenerateKey(jwk.AlgorithmHS256, jwk.WithRandom(bytes.NewReader(seed)))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if !bytes.Equal(k2.IntoKey().([]byte), seed[:32]) {
+			t.Fatalf("expected secret from random reader, but got %x", k2.IntoKey())
+		}
+	})
+	t.Run("options", func(t *testing.T) {
+		k, err := jwk.GenerateKey(jwk.AlgorithmPS256,
+			jwk.WithRSABits(3072),
+			jwk.WithKeyOps{jwk.KeyOpSign, jwk.KeyOpVerify},
+			jwk.WithKeyID("my-key"),
+		)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k.IntoKey().(*rsa.PrivateKey).N.BitLen() != 3072 {
+			t.Fatalf("expected 3072 bits, but got %d", k.IntoKey().(*rsa.PrivateKey).N.BitLen())
+		}
+		if k.Use().Exist() || !k.KeyOps().All(jwk.KeyOpSign, jwk.KeyOpVerify) {
+			t.Fatalf("expected only key_ops, but got use %s, key_ops %v", k.Use(), k.KeyOps().AsSlice())
+		}
+		if k.Kid() != "my-key" {
+			t.Fatalf("expected %s, but got %s", "my-key", k.Kid())
+		}
+		k, err = jwk.GenerateKey(jwk.AlgorithmES384, jwk.WithKeyUse(jwk.KeyUseEnc), jwk.WithKeyIDFunc(jwk.KeyIDRandomUUID))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k.Use() != jwk.KeyUseEnc || len(k.Kid()) != 36 {
+			t.Fatalf("expected enc with uuid kid, but got %s, %s", k.Use(), k.Kid())
+		}
+	})
+	t.Run("registered curve", func(t *testing.T) {
+		// registered curve can't be unregistered, so test only name and distinct curve are used
+		// to keep it from other tests of package
+		params := *elliptic.P224().Params()
+		jwk.RegisterCurve("P-224-generate", &params, "ES224-generate")
+		k, err := jwk.GenerateKey("ES224-generate")
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k.IntoKey().(*ecdsa.PrivateKey).Curve != &params {
+			t.Fatalf("expected P-224, but got %v", k.IntoKey().(*ecdsa.PrivateKey).Curve.Params().Name)
+		}
+	})
+	t.Run("small rsa", func(t *testing.T) {
+		_, err := jwk.GenerateKey(jwk.AlgorithmRS256, jwk.WithRSABits(1024))
+		if !errors.Is(err, jwk.ErrInsufficientKeySize) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrInsufficientKeySize, err)
+		}
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		for _, alg := range []jwk.Algorithm{jwk.AlgorithmNone, jwk.AlgorithmDir, jwk.AlgorithmPBES2_HS256_A128KW, "unknown"} {
+			_, err := jwk.GenerateKey(alg)
+			if !errors.Is(err, jwk.ErrUnsupportedAlgorithm) {
+				t.Fatalf("%s : expected %v, but got %v", alg, jwk.ErrUnsupportedAlgorithm, err)
+			}
+		}
+	})
+	t.Run("random error", func(t *testing.T) {
+		_, err := jwk.GenerateKey(jwk.AlgorithmHS256, jwk.WithRandom(bytes.NewReader(nil)))
+		if err == nil {
+			t.Fatalf("expected error, but got <nil>")
+		}
+	})
+}
@@ -1,8 +1,8 @@
 module github.com/egoavara/jwk
 
-go 1.17
+go 1.20
 
 require (
-	github.com/golang-jwt/jwt/v4 v4.2.0 // indirect
-	github.com/pkg/errors v0.9.1 // indirect
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.2.0
+	github.com/golang-jwt/jwt/v4 v4.2.0
 )
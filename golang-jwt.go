@@ -1,7 +1,9 @@
 package jwk
 
 import (
+	"crypto"
 	"errors"
+	"fmt"
 
 	"github.com/golang-jwt/jwt/v4"
 )
@@ -38,6 +40,11 @@ type (
 )
 
 var ErrNoKeyForVerifier = errors.New("no key for verifier")
+
+// golang-jwt/jwt does not provide ES256K, so it is registered by this package
+// https://www.rfc-editor.org/rfc/rfc8812.html#section-3.2
+var SigningMethodES256K = &jwt.SigningMethodECDSA{Name: string(AlgorithmES256K), Hash: crypto.SHA256, KeySize: 32, CurveBits: 256}
+
 var (
 	signingMethodTable = map[Algorithm]jwt.SigningMethod{
 		AlgorithmRS256: jwt.SigningMethodRS256,
@@ -48,6 +55,8 @@ var (
 		AlgorithmES384: jwt.SigningMethodES384,
 		AlgorithmES512: jwt.SigningMethodES512,
 
+		AlgorithmES256K: SigningMethodES256K,
+
 		AlgorithmHS256: jwt.SigningMethodHS256,
 		AlgorithmHS384: jwt.SigningMethodHS384,
 		AlgorithmHS512: jwt.SigningMethodHS512,
@@ -56,10 +65,18 @@ var (
 		AlgorithmPS384: jwt.SigningMethodPS384,
 		AlgorithmPS512: jwt.SigningMethodPS512,
 
+		AlgorithmEdDSA: jwt.SigningMethodEdDSA,
+
 		AlgorithmNone: jwt.SigningMethodNone,
 	}
 )
 
+func init() {
+	jwt.RegisterSigningMethod(SigningMethodES256K.Alg(), func() jwt.SigningMethod {
+		return SigningMethodES256K
+	})
+}
+
 // var jwtSigningMethod = map[Algorithm]jwt.SigningMethod{
 
 // }
@@ -81,11 +98,20 @@ func LetKeyfunc(source interface{}, options ...OptionalJWTVerifier) jwt.Keyfunc
 	}
 	return nil
 }
+
+// LetSigningMethod return signing method of `GuessSigningAlgorithm`, it return nil when key can't sign
 func LetSigningMethod(key Key) jwt.SigningMethod {
-	return signingMethodTable[GuessAlgorithm(key)]
+	return lookupSigningMethod(GuessSigningAlgorithm(key))
 }
 func LetSign(key Key, claim jwt.Claims) (*jwt.Token, string, error) {
-	var token = jwt.New(LetSigningMethod(key))
+	if err := checkPermits(key, KeyOpSign); err != nil {
+		return nil, "", err
+	}
+	method := LetSigningMethod(key)
+	if method == nil {
+		return nil, "", makeErrors(ErrIncompatibleAlgorithm, fmt.Errorf("no signing method for kty='%s', alg='%s'", key.Kty(), key.Alg()))
+	}
+	var token = jwt.New(method)
 	token.Claims = claim
 	sign, err := token.SignedString(key.IntoPrivateKey())
 	if err != nil {
@@ -156,6 +182,9 @@ func (ver *JWTVerifierFromFetcher) Keyfunc(tk *jwt.Token) (interface{}, error) {
 		k = set.GetUniqueKey(tk.Header["kid"].(string), Algorithm(tk.Header["alg"].(string)).IntoKeyType())
 	}
 	if k != nil {
+		if err := checkPermits(k, KeyOpVerify); err != nil {
+			return nil, err
+		}
 		if itf := k.IntoPublicKey(); itf != nil {
 			return itf, nil
 		} else {
@@ -166,6 +195,9 @@ func (ver *JWTVerifierFromFetcher) Keyfunc(tk *jwt.Token) (interface{}, error) {
 	return nil, ErrNoKeyForVerifier
 }
 func (ver *JWTVerifierFromKey) Keyfunc(tk *jwt.Token) (interface{}, error) {
+	if err := checkPermits(ver.Key, KeyOpVerify); err != nil {
+		return nil, err
+	}
 	if isValidKeyForToken(ver.Key, tk) {
 		if itf := ver.Key.IntoPublicKey(); itf != nil {
 			return itf, nil
@@ -184,6 +216,9 @@ func (ver *JWTVerifierFromSet) Keyfunc(tk *jwt.Token) (interface{}, error) {
 		k = ver.Set.GetUniqueKey(tk.Header["kid"].(string), Algorithm(tk.Header["alg"].(string)).IntoKeyType())
 	}
 	if k != nil {
+		if err := checkPermits(k, KeyOpVerify); err != nil {
+			return nil, err
+		}
 		if itf := k.IntoPublicKey(); itf != nil {
 			return itf, nil
 		} else {
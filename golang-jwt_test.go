@@ -2,6 +2,7 @@ package jwk_test
 
 import (
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
@@ -9,6 +10,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/egoavara/jwk"
 	"github.com/golang-jwt/jwt/v4"
 )
@@ -203,4 +205,32 @@ func TestGolangJWT(t *testing.T) {
 		checkJWT(t, key)
 	})
 
+	t.Run("ES256K", func(t *testing.T) {
+		key, err := jwk.NewKey(mustECDSA(secp256k1.S256()), jwk.AlgorithmES256K)
+		if err != nil {
+			t.Fatalf("expecte <nil>, but got %v", err)
+		}
+		checkJWT(t, key)
+	})
+	t.Run("ES256K without alg", func(t *testing.T) {
+		key, err := jwk.NewKey(mustECDSA(secp256k1.S256()))
+		if err != nil {
+			t.Fatalf("expecte <nil>, but got %v", err)
+		}
+		if jwk.LetSigningMethod(key) != jwk.SigningMethodES256K {
+			t.Fatalf("expected %v, but got %v", jwk.SigningMethodES256K, jwk.LetSigningMethod(key))
+		}
+	})
+
+	t.Run("EdDSA", func(t *testing.T) {
+		_, prik, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("expecte <nil>, but got %v", err)
+		}
+		key, err := jwk.NewKey(prik, jwk.AlgorithmEdDSA)
+		if err != nil {
+			t.Fatalf("expecte <nil>, but got %v", err)
+		}
+		checkJWT(t, key)
+	})
 }
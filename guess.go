@@ -1,44 +1,256 @@
 package jwk
 
-import "crypto/ecdsa"
-
-var (
-	ecBitSizeToAlg = map[int]Algorithm{
-		256: AlgorithmES256,
-		384: AlgorithmES384,
-		521: AlgorithmES512,
-	}
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
 )
 
+// GuessPolicy decide which algorithm is guessed for key without `alg`
+type GuessPolicy struct {
+	// Candidate algorithms in preference order, if empty, `DefaultGuessOrder` is used
+	// Algorithms registered by `RegisterAlgorithm` or `RegisterCurve` are candidates after them
+	Order []Algorithm
+	// If true, RSASSA-PSS(PS*) is preferred to RSASSA-PKCS1-v1_5(RS*)
+	PreferPSS bool
+}
+
+// DefaultGuessOrder is preference order of algorithms for `DefaultGuessPolicy`
+// HMAC is ordered from strongest, so it is decided by secret length
+// `none`, `dir` and PBES2 are never guessed
+var DefaultGuessOrder = []Algorithm{
+	AlgorithmES256, AlgorithmES384, AlgorithmES512, AlgorithmES256K,
+	AlgorithmEdDSA,
+	AlgorithmRS256, AlgorithmRS384, AlgorithmRS512,
+	AlgorithmPS256, AlgorithmPS384, AlgorithmPS512,
+	AlgorithmHS512, AlgorithmHS384, AlgorithmHS256,
+	AlgorithmECDHES, AlgorithmECDHES_A128KW, AlgorithmECDHES_A192KW, AlgorithmECDHES_A256KW,
+	AlgorithmRSAOAEP256, AlgorithmRSAOAEP, AlgorithmRSA1_5,
+	AlgorithmA256KW, AlgorithmA192KW, AlgorithmA128KW,
+	AlgorithmA256GCMKW, AlgorithmA192GCMKW, AlgorithmA128GCMKW,
+}
+
+// DefaultGuessPolicy is used by `GuessAlgorithm` and `GuessAlgorithms`
+var DefaultGuessPolicy = &GuessPolicy{}
+
+// GuessAlgorithm return `alg` of key, or most preferred algorithm of `DefaultGuessPolicy` when key doesn't have `alg`
+// It return "" when there is no compatible algorithm
+// Result can be key management algorithm like `ECDH-ES`, use `GuessSigningAlgorithm` for signing
 func GuessAlgorithm(key Key) Algorithm {
+	return DefaultGuessPolicy.GuessAlgorithm(key)
+}
+
+// GuessSigningAlgorithm is same as `GuessAlgorithm`, but it return only signature algorithm
+func GuessSigningAlgorithm(key Key) Algorithm {
+	return DefaultGuessPolicy.GuessSigningAlgorithm(key)
+}
+
+// GuessAlgorithms return every compatible algorithms of key in preference order of `DefaultGuessPolicy`
+func GuessAlgorithms(key Key) []Algorithm {
+	return DefaultGuessPolicy.GuessAlgorithms(key)
+}
+
+func (policy *GuessPolicy) GuessAlgorithm(key Key) Algorithm {
 	if key.Alg().Exist() {
 		return key.Alg()
 	}
-	switch key.Kty() {
-	case KeyTypeEC:
-		if pubk := key.IntoPublicKey().(*ecdsa.PublicKey); pubk != nil {
-			return ecBitSizeToAlg[pubk.Params().BitSize]
+	if algs := policy.GuessAlgorithms(key); len(algs) > 0 {
+		return algs[0]
+	}
+	return ""
+}
+
+// GuessSigningAlgorithm return `alg` of key when it is signature algorithm, or most preferred signature algorithm
+// when key doesn't have `alg`, it return "" when there is no compatible signature algorithm
+func (policy *GuessPolicy) GuessSigningAlgorithm(key Key) Algorithm {
+	for _, alg := range policy.GuessAlgorithms(key) {
+		if info := lookupAlgorithm(alg); info != nil && info.Family == AlgorithmFamilySignature {
+			return alg
 		}
-	case KeyTypeOctet:
-		return ""
 	}
 	return ""
+}
+
+// GuessAlgorithms return every compatible algorithms of key in preference order
+// If key has `alg`, it return only `alg`
+// If key has `use`, algorithms for other `use` are skipped
+func (policy *GuessPolicy) GuessAlgorithms(key Key) []Algorithm {
+	if key.Alg().Exist() {
+		return []Algorithm{key.Alg()}
+	}
+	res := make([]Algorithm, 0)
+	seen := make(map[Algorithm]struct{})
+	for _, alg := range policy.order() {
+		if _, ok := seen[alg]; ok {
+			continue
+		}
+		seen[alg] = struct{}{}
+		info := lookupAlgorithm(alg)
+		if info == nil || info.Family == AlgorithmFamilyContentEncryption {
+			continue
+		}
+		if key.Use() == KeyUseSig && info.Family != AlgorithmFamilySignature ||
+			key.Use() == KeyUseEnc && info.Family == AlgorithmFamilySignature {
+			continue
+		}
+		if checkKeyMaterial(key, alg, info) == nil {
+			res = append(res, alg)
+		}
+	}
+	return res
+}
+
+func (policy *GuessPolicy) order() []Algorithm {
+	order := policy.Order
+	if len(order) == 0 {
+		order = DefaultGuessOrder
+	}
+	res := make([]Algorithm, 0, len(order))
+	if policy.PreferPSS {
+		// PS* take place of first RS*
+		var pss []Algorithm
+		for _, alg := range order {
+			if isPSS(alg) {
+				pss = append(pss, alg)
+			}
+		}
+		for _, alg := range order {
+			switch {
+			case isPSS(alg):
+			case isPKCS1v15(alg) && pss != nil:
+				res = append(res, pss...)
+				res = append(res, alg)
+				pss = nil
+			default:
+				res = append(res, alg)
+			}
+		}
+		res = append(res, pss...)
+	} else {
+		res = append(res, order...)
+	}
+	return append(res, registeredAlgorithms()...)
+}
+
+func isPSS(alg Algorithm) bool {
+	return alg == AlgorithmPS256 || alg == AlgorithmPS384 || alg == AlgorithmPS512
+}
+
+func isPKCS1v15(alg Algorithm) bool {
+	return alg == AlgorithmRS256 || alg == AlgorithmRS384 || alg == AlgorithmRS512
+}
+
+// checkKeyMaterial check key type, curve and key size of key for algorithm
+func checkKeyMaterial(key Key, alg Algorithm, info *AlgorithmInfo) error {
+	if !alg.allowKeyType(key.Kty()) {
+		return makeErrors(ErrIncompatibleAlgorithm, FieldError("kty"), fmt.Errorf("'%s' can't be used with kty='%s'", alg, key.Kty()))
+	}
+	if crv := keyCurveName(key); isCurveAlgorithm(alg) {
+		if !isCompatibleCurve(key, alg) {
+			return makeErrors(ErrIncompatibleAlgorithm, FieldError("crv"), fmt.Errorf("'%s' can't be used with crv='%s'", alg, crv))
+		}
+	} else if len(info.Curves) > 0 {
+		found := false
+		for _, c := range info.Curves {
+			found = found || c == crv
+		}
+		if !found {
+			return makeErrors(ErrIncompatibleAlgorithm, FieldError("crv"), fmt.Errorf("'%s' can't be used with crv='%s'", alg, crv))
+		}
+	}
+	if info.KeySize == 0 {
+		return nil
+	}
+	var size int
+	switch k := key.IntoPublicKey().(type) {
+	case *rsa.PublicKey:
+		size = k.N.BitLen()
+	case []byte:
+		size = len(k) * 8
+	default:
+		return nil
+	}
+	switch {
+	case info.ExactKeySize && size != info.KeySize:
+		return makeErrors(ErrIncompatibleAlgorithm, ErrInsufficientKeySize, fmt.Errorf("'%s' need %d bits key, but got %d bits", alg, info.KeySize, size))
+	case size < info.KeySize:
+		return makeErrors(ErrIncompatibleAlgorithm, ErrInsufficientKeySize, fmt.Errorf("'%s' need %d bits or larger key, but got %d bits", alg, info.KeySize, size))
+	}
+	return nil
+}
 
+// keyCurveName return `crv` of key, "" if key doesn't have curve
+func keyCurveName(key Key) string {
+	switch k := key.IntoPublicKey().(type) {
+	case *ecdsa.PublicKey:
+		return curveName(k.Curve)
+	case ed25519.PublicKey:
+		return curveEd25519
+	case *ecdh.PublicKey:
+		if k.Curve() == ecdh.X25519() {
+			return curveX25519
+		}
+	}
+	if crv, ok := key.Extra()["crv"].(string); ok {
+		return crv
+	}
+	return ""
 }
 
 // In RFC, key's `alg` header is optional
 // So if there is no defined algorithm, you need to guess it is compatible algorithm
+// See `CheckCompatibleKey` for reason of incompatible
 func IsCompatibleKey(key Key, alg Algorithm) bool {
-	if len(alg) <= 0 {
-		// TODO : panic?
-		return false
-	}
-	if keyalg := key.Alg(); len(keyalg) > 0 {
-		return keyalg == alg
-	}
-	// TODO : key check steps
-	// if key.Kty() != alg.IntoKeyType() {
-	// 	return false
-	// }
-	return false
+	return CheckCompatibleKey(key, alg) == nil
+}
+
+// CheckCompatibleKey return reason why key can't be used to verify with algorithm, or nil when it can
+//   - If key has `alg`, it must be same with algorithm, and key material is not checked
+//   - `kty` must be one of `Algorithm.IntoKeyTypes`
+//   - `crv` must be curve of algorithm, for example `P-256` for `ES256`
+//   - RSA modulus and `oct` secret must be long enough for algorithm
+//   - `use` and `key_ops` must permit verification, or unwrapping for key management algorithm
+func CheckCompatibleKey(key Key, alg Algorithm) error {
+	if !alg.Exist() {
+		return makeErrors(ErrParameter, ErrIncompatibleAlgorithm, fmt.Errorf("alg is empty"))
+	}
+	if keyalg := key.Alg(); keyalg.Exist() && keyalg != alg {
+		return makeErrors(ErrIncompatibleAlgorithm, FieldError("alg"), fmt.Errorf("expected alg='%s', but key has alg='%s'", alg, keyalg))
+	}
+	info := lookupAlgorithm(alg)
+	switch {
+	case info == nil && key.Alg() == alg:
+		// unknown algorithm, but key says it is for that algorithm
+		return nil
+	case info == nil:
+		return makeErrors(ErrIncompatibleAlgorithm, ErrUnsupportedAlgorithm, fmt.Errorf("can't decide key for unknown alg='%s'", alg))
+	case key.Alg() != alg:
+		if err := checkKeyMaterial(key, alg, info); err != nil {
+			return err
+		}
+	}
+	return checkKeyUsage(key, alg, info)
+}
+
+// checkKeyUsage check `use` and `key_ops` of key permit verification or unwrapping for algorithm
+func checkKeyUsage(key Key, alg Algorithm, info *AlgorithmInfo) error {
+	if alg == AlgorithmNone {
+		return nil
+	}
+	ops := []KeyOp{KeyOpVerify}
+	if info.Family != AlgorithmFamilySignature {
+		ops = []KeyOp{KeyOpUnwrapKey, KeyOpDecrypt, KeyOpDeriveKey, KeyOpDeriveBits}
+	}
+	for _, op := range ops {
+		if key.Permits(op) {
+			return nil
+		}
+	}
+	field := FieldError("key_ops")
+	if key.Use().Exist() && key.Use() != ops[0].intoUse() {
+		field = FieldError("use")
+	}
+	return makeErrors(ErrIncompatibleAlgorithm, field, checkPermits(key, ops[0]))
 }
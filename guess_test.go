@@ -0,0 +1,179 @@
+package jwk_test
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestGuessAlgorithm(t *testing.T) {
+	rsak := jwk.MustKey(mustRSA())
+	t.Run("RSA", func(t *testing.T) {
+		if alg := jwk.GuessAlgorithm(rsak); alg != jwk.AlgorithmRS256 {
+			t.Fatalf("expected %v, but got %v", jwk.AlgorithmRS256, alg)
+		}
+		checkJWT(t, rsak)
+	})
+	t.Run("RSA prefer PSS", func(t *testing.T) {
+		policy := &jwk.GuessPolicy{PreferPSS: true}
+		if alg := policy.GuessAlgorithm(rsak); alg != jwk.AlgorithmPS256 {
+			t.Fatalf("expected %v, but got %v", jwk.AlgorithmPS256, alg)
+		}
+	})
+	t.Run("HMAC by secret length", func(t *testing.T) {
+		for size, expected := range map[int]jwk.Algorithm{
+			32: jwk.AlgorithmHS256,
+			48: jwk.AlgorithmHS384,
+			64: jwk.AlgorithmHS512,
+			16: jwk.AlgorithmA128KW,
+			8:  "",
+		} {
+			k := jwk.MustKey(make([]byte, size))
+			if alg := jwk.GuessAlgorithm(k); alg != expected {
+				t.Fatalf("expected %v for %d bytes, but got %v", expected, size, alg)
+			}
+		}
+	})
+	t.Run("EdDSA", func(t *testing.T) {
+		_, prik, _ := ed25519.GenerateKey(nil)
+		if alg := jwk.GuessAlgorithm(jwk.MustKey(prik)); alg != jwk.AlgorithmEdDSA {
+			t.Fatalf("expected %v, but got %v", jwk.AlgorithmEdDSA, alg)
+		}
+	})
+	t.Run("EC", func(t *testing.T) {
+		if alg := jwk.GuessAlgorithm(jwk.MustKey(mustECDSA(elliptic.P384()))); alg != jwk.AlgorithmES384 {
+			t.Fatalf("expected %v, but got %v", jwk.AlgorithmES384, alg)
+		}
+	})
+	t.Run("signing", func(t *testing.T) {
+		x25519, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		k := jwk.MustKey(x25519)
+		if alg := jwk.GuessAlgorithm(k); alg != jwk.AlgorithmECDHES {
+			t.Fatalf("expected %v, but got %v", jwk.AlgorithmECDHES, alg)
+		}
+		if alg := jwk.GuessSigningAlgorithm(k); alg != "" {
+			t.Fatalf("expected empty, but got %v", alg)
+		}
+		if alg := jwk.GuessSigningAlgorithm(jwk.MustKey(mustRSA(), jwk.AlgorithmRSAOAEP)); alg != "" {
+			t.Fatalf("expected empty, but got %v", alg)
+		}
+		if alg := jwk.GuessSigningAlgorithm(rsak); alg != jwk.AlgorithmRS256 {
+			t.Fatalf("expected %v, but got %v", jwk.AlgorithmRS256, alg)
+		}
+	})
+	t.Run("alg", func(t *testing.T) {
+		k := jwk.MustKey(mustECDSA(elliptic.P256()), jwk.AlgorithmECDHES)
+		if algs := jwk.GuessAlgorithms(k); !reflect.DeepEqual(algs, []jwk.Algorithm{jwk.AlgorithmECDHES}) {
+			t.Fatalf("expected %v, but got %v", []jwk.Algorithm{jwk.AlgorithmECDHES}, algs)
+		}
+	})
+}
+
+func TestGuessAlgorithms(t *testing.T) {
+	rsak := jwk.MustKey(mustRSA())
+	t.Run("RSA", func(t *testing.T) {
+		expected := []jwk.Algorithm{
+			jwk.AlgorithmRS256, jwk.AlgorithmRS384, jwk.AlgorithmRS512,
+			jwk.AlgorithmPS256, jwk.AlgorithmPS384, jwk.AlgorithmPS512,
+			jwk.AlgorithmRSAOAEP256, jwk.AlgorithmRSAOAEP, jwk.AlgorithmRSA1_5,
+		}
+		// algorithms registered by other tests can be after builtin algorithms
+		if algs := jwk.GuessAlgorithms(rsak); len(algs) < len(expected) || !reflect.DeepEqual(algs[:len(expected)], expected) {
+			t.Fatalf("expected %v, but got %v", expected, algs)
+		}
+	})
+	t.Run("use", func(t *testing.T) {
+		k := jwk.MustKey(mustRSA())
+		k.(*jwk.RSAPrivateKey).KeyUse = jwk.KeyUseEnc
+		expected := []jwk.Algorithm{jwk.AlgorithmRSAOAEP256, jwk.AlgorithmRSAOAEP, jwk.AlgorithmRSA1_5}
+		if algs := jwk.GuessAlgorithms(k); !reflect.DeepEqual(algs, expected) {
+			t.Fatalf("expected %v, but got %v", expected, algs)
+		}
+	})
+	t.Run("oct", func(t *testing.T) {
+		expected := []jwk.Algorithm{jwk.AlgorithmHS256, jwk.AlgorithmA256KW, jwk.AlgorithmA256GCMKW}
+		if algs := jwk.GuessAlgorithms(jwk.MustKey(make([]byte, 32))); len(algs) < len(expected) || !reflect.DeepEqual(algs[:len(expected)], expected) {
+			t.Fatalf("expected %v, but got %v", expected, algs)
+		}
+	})
+	t.Run("order", func(t *testing.T) {
+		policy := &jwk.GuessPolicy{Order: []jwk.Algorithm{jwk.AlgorithmPS512, jwk.AlgorithmHS256}}
+		if algs := policy.GuessAlgorithms(rsak); len(algs) == 0 || algs[0] != jwk.AlgorithmPS512 {
+			t.Fatalf("expected %v first, but got %v", jwk.AlgorithmPS512, algs)
+		}
+	})
+}
+
+func TestCheckCompatibleKey(t *testing.T) {
+	rsak := jwk.MustKey(mustRSA())
+	t.Run("without alg", func(t *testing.T) {
+		for _, tc := range []struct {
+			key jwk.Key
+			alg jwk.Algorithm
+		}{
+			{rsak, jwk.AlgorithmRS256},
+			{rsak, jwk.AlgorithmPS512},
+			{jwk.MustKey(mustECDSA(elliptic.P384())), jwk.AlgorithmES384},
+			{jwk.MustKey(make([]byte, 64)), jwk.AlgorithmHS256},
+			{jwk.MustKey(make([]byte, 64)), jwk.AlgorithmHS512},
+		} {
+			if err := jwk.CheckCompatibleKey(tc.key, tc.alg); err != nil {
+				t.Fatalf("expected <nil> for %v, but got %v", tc.alg, err)
+			}
+			if !jwk.IsCompatibleKey(tc.key, tc.alg) {
+				t.Fatalf("expected true for %v, but got false", tc.alg)
+			}
+		}
+	})
+	t.Run("verify without alg", func(t *testing.T) {
+		checkJWT(t, jwk.MustKey(mustECDSA(elliptic.P256())))
+		checkJWT(t, jwk.MustKey(make([]byte, 48)))
+	})
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("expected <nil>, but got %v", err)
+	}
+	sigk := jwk.MustKey(mustRSA())
+	sigk.(*jwk.RSAPrivateKey).KeyOperations[jwk.KeyOpSign] = struct{}{}
+	enck := jwk.MustKey(mustRSA())
+	enck.(*jwk.RSAPrivateKey).KeyUse = jwk.KeyUseEnc
+	for name, tc := range map[string]struct {
+		key    jwk.Key
+		alg    jwk.Algorithm
+		reason error
+	}{
+		"alg":            {jwk.MustKey(mustRSA(), jwk.AlgorithmRS256), jwk.AlgorithmPS256, jwk.FieldError("alg")},
+		"kty":            {rsak, jwk.AlgorithmES256, jwk.FieldError("kty")},
+		"crv":            {jwk.MustKey(mustECDSA(elliptic.P384())), jwk.AlgorithmES256, jwk.FieldError("crv")},
+		"small modulus":  {jwk.MustKey(smallRSA), jwk.AlgorithmRS256, jwk.ErrInsufficientKeySize},
+		"short secret":   {jwk.MustKey(make([]byte, 32)), jwk.AlgorithmHS512, jwk.ErrInsufficientKeySize},
+		"use":            {enck, jwk.AlgorithmRS256, jwk.FieldError("use")},
+		"key_ops":        {sigk, jwk.AlgorithmRS256, jwk.FieldError("key_ops")},
+		"unknown alg":    {rsak, "unknown", jwk.ErrUnsupportedAlgorithm},
+		"empty alg":      {rsak, "", jwk.ErrParameter},
+		"wrong aes size": {jwk.MustKey(make([]byte, 24)), jwk.AlgorithmA128KW, jwk.ErrInsufficientKeySize},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := jwk.CheckCompatibleKey(tc.key, tc.alg)
+			if !errors.Is(err, jwk.ErrIncompatibleAlgorithm) {
+				t.Fatalf("expected %v is %v, but not", err, jwk.ErrIncompatibleAlgorithm)
+			}
+			if !errors.Is(err, tc.reason) {
+				t.Fatalf("expected %v is %v, but not", err, tc.reason)
+			}
+			if jwk.IsCompatibleKey(tc.key, tc.alg) {
+				t.Fatalf("expected false, but got true")
+			}
+		})
+	}
+}
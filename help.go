@@ -82,7 +82,11 @@ func utilConsumeArrMap(m map[string]interface{}, k string) ([]map[string]interfa
 			delete(m, k)
 			res := make([]map[string]interface{}, len(s))
 			for i, is := range s {
-				res[i] = is.(map[string]interface{})
+				im, ok := is.(map[string]interface{})
+				if !ok {
+					return nil, makeErrors(ErrInvalidArrayObject, IndexError(i))
+				}
+				res[i] = im
 			}
 			return res, nil
 		}
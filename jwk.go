@@ -1,11 +1,18 @@
 package jwk
 
 import (
+	"context"
 	"crypto"
+	"crypto/ecdh"
 	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/url"
 )
 
@@ -35,6 +42,20 @@ type Key interface {
 	IntoKey() interface{}
 	IntoPublicKey() crypto.PublicKey
 	IntoPrivateKey() crypto.PrivateKey
+	// https://www.rfc-editor.org/rfc/rfc7638
+	Thumbprint(hash crypto.Hash) ([]byte, error)
+	// PKCS #8 for private key, PKIX for public key
+	MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error)
+	// Public key of key, private key return public key with same `kid`, `use`, `alg` and `x5*`
+	// Public key return its clone, symetric key and unknown key with private members return nil
+	// Result never share material with key, so changing result doesn't change key
+	Public() Key
+	// Deep copy of key, it is safe to modify without changing origin
+	Clone() Key
+	// Equal report whether key is same with other, see `EqualMode`
+	Equal(other Key, mode EqualMode) bool
+	// Permits report whether key can be used for op by `use` and `key_ops`
+	Permits(op KeyOp) bool
 	//
 	intoUnknown() *UnknownKey
 	intoBaseKey() *BaseKey
@@ -52,6 +73,7 @@ type (
 		X509CertThumbprint     []byte                 // https://datatracker.ietf.org/doc/html/rfc7517#section-4.8
 		X509CertThumbprintS256 []byte                 // https://datatracker.ietf.org/doc/html/rfc7517#section-4.9
 		extra                  map[string]interface{} // extra fields for Key
+		keyOpsOrder            []KeyOp                // order of `key_ops` when it decoded, for `KeyOpsDeclared`
 	}
 	UnknownKey struct {
 		BaseKey
@@ -83,6 +105,27 @@ type (
 		BaseKey
 		Key []byte
 	}
+	// https://www.rfc-editor.org/rfc/rfc8037#section-2
+	// Only Ed25519 is supported, OKP key of Ed448 is decoded as `*UnknownKey`
+	OKPPrivateKey struct {
+		BaseKey
+		Key ed25519.PrivateKey
+	}
+	// https://www.rfc-editor.org/rfc/rfc8037#section-2
+	OKPPublicKey struct {
+		BaseKey
+		Key ed25519.PublicKey
+	}
+	// https://www.rfc-editor.org/rfc/rfc8037#section-3.2
+	ECDHPrivateKey struct {
+		BaseKey
+		Key *ecdh.PrivateKey
+	}
+	// https://www.rfc-editor.org/rfc/rfc8037#section-3.2
+	ECDHPublicKey struct {
+		BaseKey
+		Key *ecdh.PublicKey
+	}
 	// TODO : https://www.rfc-editor.org/rfc/rfc7518#section-4
 	// TODO : https://www.rfc-editor.org/rfc/rfc7518#section-5
 )
@@ -92,6 +135,11 @@ type (
 		WithNewKey(Key, *BaseKey) error
 	}
 	WithAlgorithm Algorithm
+	// WithKeyID set `kid` of key
+	WithKeyID string
+	// WithKeyIDFunc set `kid` of key from result of function, like `KeyIDThumbprint`, `KeyIDRandomUUID`
+	// It can be used for `EncodeSet`, then only key without `kid` get `kid`
+	WithKeyIDFunc func(Key) (string, error)
 )
 
 // data is one of
@@ -99,6 +147,10 @@ type (
 // - *rsa.PublicKey		-> *RSAPublicKey
 // - *ecdsa.PrivateKey	-> *ECPrivateKey
 // - *ecdsa.PublicKey	-> *ECPublicKey
+// - ed25519.PrivateKey	-> *OKPPrivateKey
+// - ed25519.PublicKey	-> *OKPPublicKey
+// - *ecdh.PrivateKey	-> *ECDHPrivateKey, only for X25519
+// - *ecdh.PublicKey	-> *ECDHPublicKey, only for X25519
 // - []byte				-> *SymetricKey
 // - string				-> *SymetricKey
 // - Key				-> (self)
@@ -137,6 +189,44 @@ func NewKey(data interface{}, options ...OptionalNewKey) (Key, error) {
 			},
 			Key: d,
 		}
+	case ed25519.PrivateKey:
+		result = &OKPPrivateKey{
+			BaseKey: BaseKey{
+				KeyOperations: map[KeyOp]struct{}{},
+				extra:         map[string]interface{}{},
+			},
+			Key: d,
+		}
+	case ed25519.PublicKey:
+		result = &OKPPublicKey{
+			BaseKey: BaseKey{
+				KeyOperations: map[KeyOp]struct{}{},
+				extra:         map[string]interface{}{},
+			},
+			Key: d,
+		}
+	case *ecdh.PrivateKey:
+		if d.Curve() != ecdh.X25519() {
+			return nil, ErrIncompatibleType
+		}
+		result = &ECDHPrivateKey{
+			BaseKey: BaseKey{
+				KeyOperations: map[KeyOp]struct{}{},
+				extra:         map[string]interface{}{},
+			},
+			Key: d,
+		}
+	case *ecdh.PublicKey:
+		if d.Curve() != ecdh.X25519() {
+			return nil, ErrIncompatibleType
+		}
+		result = &ECDHPublicKey{
+			BaseKey: BaseKey{
+				KeyOperations: map[KeyOp]struct{}{},
+				extra:         map[string]interface{}{},
+			},
+			Key: d,
+		}
 	case []byte:
 		result = &SymetricKey{
 			BaseKey: BaseKey{
@@ -208,7 +298,7 @@ func (set *Set) GetUniqueKey(kid string, kty KeyType) Key {
 }
 
 func (alg Algorithm) WithNewKey(k Key, bk *BaseKey) error {
-	if alg.IntoKeyType() == k.Kty() {
+	if alg.allowKeyType(k.Kty()) && isCompatibleCurve(k, alg) {
 		bk.Algorithm = alg
 		return nil
 	}
@@ -216,9 +306,50 @@ func (alg Algorithm) WithNewKey(k Key, bk *BaseKey) error {
 }
 func (w WithAlgorithm) WithNewKey(k Key, bk *BaseKey) error {
 	alg := Algorithm(w)
-	if alg.IntoKeyType() == k.Kty() {
+	if alg.allowKeyType(k.Kty()) && isCompatibleCurve(k, alg) {
 		bk.Algorithm = alg
 		return nil
 	}
 	return ErrIncompatibleAlgorithm
 }
+
+func (w WithKeyID) WithNewKey(k Key, bk *BaseKey) error {
+	bk.KeyID = string(w)
+	return nil
+}
+func (w WithKeyIDFunc) WithNewKey(k Key, bk *BaseKey) error {
+	kid, err := w(k)
+	if err != nil {
+		return err
+	}
+	bk.KeyID = kid
+	return nil
+}
+func (w WithKeyIDFunc) WithEncodeSet(ctx context.Context) context.Context {
+	var option *OptionEncodeSet
+	ctx = MustGetOptionFromContext(ctx, &option, true)
+	option.AssignKeyID = w
+	return ctx
+}
+
+// KeyIDThumbprint return base64url encoded JWK Thumbprint using SHA-256
+// https://www.rfc-editor.org/rfc/rfc7638#section-3.1
+func KeyIDThumbprint(key Key) (string, error) {
+	tp, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tp), nil
+}
+
+// KeyIDRandomUUID return random UUID(version 4)
+// https://www.rfc-editor.org/rfc/rfc4122#section-4.4
+func KeyIDRandomUUID(key Key) (string, error) {
+	var uuid [16]byte
+	if _, err := io.ReadFull(rand.Reader, uuid[:]); err != nil {
+		return "", err
+	}
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16]), nil
+}
@@ -0,0 +1,67 @@
+package jwk_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestNewKeyID(t *testing.T) {
+	eck, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	t.Run("explicit", func(t *testing.T) {
+		k, err := jwk.NewKey(eck, jwk.WithKeyID("my-key"))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k.Kid() != "my-key" {
+			t.Fatalf("expected %v, but got %v", "my-key", k.Kid())
+		}
+	})
+	t.Run("thumbprint", func(t *testing.T) {
+		k, err := jwk.NewKey(eck, jwk.WithKeyIDFunc(jwk.KeyIDThumbprint))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		expected, err := jwk.KeyIDThumbprint(jwk.MustKey(&eck.PublicKey))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k.Kid() != expected {
+			t.Fatalf("expected %v, but got %v", expected, k.Kid())
+		}
+	})
+	t.Run("random uuid", func(t *testing.T) {
+		k, err := jwk.NewKey(eck, jwk.WithKeyIDFunc(jwk.KeyIDRandomUUID))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(k.Kid()) {
+			t.Fatalf("expected uuid v4, but got %v", k.Kid())
+		}
+		k2 := jwk.MustKey(eck, jwk.WithKeyIDFunc(jwk.KeyIDRandomUUID))
+		if k.Kid() == k2.Kid() {
+			t.Fatalf("expected different uuid, but got %v twice", k.Kid())
+		}
+	})
+	t.Run("function", func(t *testing.T) {
+		k, err := jwk.NewKey(eck, jwk.WithKeyIDFunc(func(k jwk.Key) (string, error) { return string(k.Kty()) + "-1", nil }))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if k.Kid() != "EC-1" {
+			t.Fatalf("expected %v, but got %v", "EC-1", k.Kid())
+		}
+	})
+	t.Run("function error", func(t *testing.T) {
+		expected := errors.New("no kid")
+		_, err := jwk.NewKey(eck, jwk.WithKeyIDFunc(func(k jwk.Key) (string, error) { return "", expected }))
+		if !errors.Is(err, expected) {
+			t.Fatalf("expected %v, but got %v", expected, err)
+		}
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/ed25519"
 	"crypto/x509"
 	"encoding/base64"
+	"fmt"
 	"math/big"
 	"net/url"
 )
@@ -28,6 +30,18 @@ func (key *ECPublicKey) Kty() KeyType {
 func (key *SymetricKey) Kty() KeyType {
 	return KeyTypeOctet
 }
+func (key *OKPPrivateKey) Kty() KeyType {
+	return KeyTypeOKP
+}
+func (key *OKPPublicKey) Kty() KeyType {
+	return KeyTypeOKP
+}
+func (key *ECDHPrivateKey) Kty() KeyType {
+	return KeyTypeOKP
+}
+func (key *ECDHPublicKey) Kty() KeyType {
+	return KeyTypeOKP
+}
 
 func (key *BaseKey) Use() KeyUse {
 	return key.KeyUse
@@ -87,7 +101,7 @@ func (set *Set) UnmarshalJSON(bts []byte) error {
 }
 
 func (key *UnknownKey) intoUnknown() *UnknownKey {
-	return key
+	return key.Clone().(*UnknownKey)
 }
 func (key *UnknownKey) MarshalJSON() ([]byte, error) {
 	buf := bytes.NewBuffer(nil)
@@ -105,7 +119,11 @@ func (key *UnknownKey) UnmarshalJSON(bts []byte) error {
 	if err != nil {
 		return err
 	}
-	*key = *(dat.intoUnknown())
+	unknown := dat.intoUnknown()
+	if unknown == nil {
+		return makeErrors(ErrCauseCustomKey, fmt.Errorf("kty='%s' can't be encoded into unknown key", dat.Kty()))
+	}
+	*key = *unknown
 	return nil
 }
 
@@ -123,7 +141,8 @@ func (key *UnknownKey) IntoPrivateKey() crypto.PrivateKey {
 
 func (key *RSAPrivateKey) intoUnknown() *UnknownKey {
 	// TODO : Fatal error?
-	extra := key.extra
+	bk := key.BaseKey.clone()
+	extra := bk.extra
 	extra["n"] = base64.RawURLEncoding.EncodeToString(key.Key.N.Bytes())
 	extra["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.Key.E)).Bytes())
 	extra["d"] = base64.RawURLEncoding.EncodeToString(key.Key.D.Bytes())
@@ -132,19 +151,11 @@ func (key *RSAPrivateKey) intoUnknown() *UnknownKey {
 	extra["dp"] = base64.RawURLEncoding.EncodeToString(key.Key.Precomputed.Dp.Bytes())
 	extra["dq"] = base64.RawURLEncoding.EncodeToString(key.Key.Precomputed.Dq.Bytes())
 	extra["qi"] = base64.RawURLEncoding.EncodeToString(key.Key.Precomputed.Qinv.Bytes())
-	// TODO : oth
+	if len(key.Key.Primes) > 2 {
+		extra["oth"] = encodeOthRSA(key.Key)
+	}
 	return &UnknownKey{
-		BaseKey: BaseKey{
-			KeyUse:                 key.Use(),
-			KeyOperations:          key.KeyOps(),
-			Algorithm:              key.Alg(),
-			KeyID:                  key.Kid(),
-			X509URL:                key.X5u(),
-			X509CertChain:          key.X5c(),
-			X509CertThumbprint:     key.X5t(),
-			X509CertThumbprintS256: key.X5tS256(),
-			extra:                  extra,
-		},
+		BaseKey: bk,
 		KeyType: key.Kty(),
 	}
 }
@@ -184,22 +195,13 @@ func (key *RSAPrivateKey) IntoPrivateKey() crypto.PrivateKey {
 
 func (key *RSAPublicKey) intoUnknown() *UnknownKey {
 	// TODO : Fatal error?
-	extra := key.extra
+	bk := key.BaseKey.clone()
+	extra := bk.extra
 	extra["n"] = base64.RawURLEncoding.EncodeToString(key.Key.N.Bytes())
 	extra["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.Key.E)).Bytes())
 	// TODO : oth
 	return &UnknownKey{
-		BaseKey: BaseKey{
-			KeyUse:                 key.Use(),
-			KeyOperations:          key.KeyOps(),
-			Algorithm:              key.Alg(),
-			KeyID:                  key.Kid(),
-			X509URL:                key.X5u(),
-			X509CertChain:          key.X5c(),
-			X509CertThumbprint:     key.X5t(),
-			X509CertThumbprintS256: key.X5tS256(),
-			extra:                  extra,
-		},
+		BaseKey: bk,
 		KeyType: key.Kty(),
 	}
 }
@@ -237,24 +239,15 @@ func (key *RSAPublicKey) IntoPrivateKey() crypto.PrivateKey {
 
 func (key *ECPrivateKey) intoUnknown() *UnknownKey {
 	// TODO : Fatal error?
-	extra := key.extra
-	extra["crv"] = key.Key.Curve.Params().Name
+	bk := key.BaseKey.clone()
+	extra := bk.extra
+	extra["crv"] = curveName(key.Key.Curve)
 	extra["x"] = base64.RawURLEncoding.EncodeToString(key.Key.X.Bytes())
 	extra["y"] = base64.RawURLEncoding.EncodeToString(key.Key.Y.Bytes())
 	extra["d"] = base64.RawURLEncoding.EncodeToString(key.Key.D.Bytes())
 	// TODO : oth
 	return &UnknownKey{
-		BaseKey: BaseKey{
-			KeyUse:                 key.Use(),
-			KeyOperations:          key.KeyOps(),
-			Algorithm:              key.Alg(),
-			KeyID:                  key.Kid(),
-			X509URL:                key.X5u(),
-			X509CertChain:          key.X5c(),
-			X509CertThumbprint:     key.X5t(),
-			X509CertThumbprintS256: key.X5tS256(),
-			extra:                  extra,
-		},
+		BaseKey: bk,
 		KeyType: key.Kty(),
 	}
 }
@@ -294,23 +287,14 @@ func (key *ECPrivateKey) IntoPrivateKey() crypto.PrivateKey {
 
 func (key *ECPublicKey) intoUnknown() *UnknownKey {
 	// TODO : Fatal error?
-	extra := key.extra
-	extra["crv"] = key.Key.Curve.Params().Name
+	bk := key.BaseKey.clone()
+	extra := bk.extra
+	extra["crv"] = curveName(key.Key.Curve)
 	extra["x"] = base64.RawURLEncoding.EncodeToString(key.Key.X.Bytes())
 	extra["y"] = base64.RawURLEncoding.EncodeToString(key.Key.Y.Bytes())
 	// TODO : oth
 	return &UnknownKey{
-		BaseKey: BaseKey{
-			KeyUse:                 key.Use(),
-			KeyOperations:          key.KeyOps(),
-			Algorithm:              key.Alg(),
-			KeyID:                  key.Kid(),
-			X509URL:                key.X5u(),
-			X509CertChain:          key.X5c(),
-			X509CertThumbprint:     key.X5t(),
-			X509CertThumbprintS256: key.X5tS256(),
-			extra:                  extra,
-		},
+		BaseKey: bk,
 		KeyType: key.Kty(),
 	}
 }
@@ -349,21 +333,12 @@ func (key *ECPublicKey) IntoPrivateKey() crypto.PrivateKey {
 
 func (key *SymetricKey) intoUnknown() *UnknownKey {
 	// TODO : Fatal error?
-	extra := key.extra
+	bk := key.BaseKey.clone()
+	extra := bk.extra
 	extra["k"] = base64.RawURLEncoding.EncodeToString(key.Key)
 	// TODO : oth
 	return &UnknownKey{
-		BaseKey: BaseKey{
-			KeyUse:                 key.Use(),
-			KeyOperations:          key.KeyOps(),
-			Algorithm:              key.Alg(),
-			KeyID:                  key.Kid(),
-			X509URL:                key.X5u(),
-			X509CertChain:          key.X5c(),
-			X509CertThumbprint:     key.X5t(),
-			X509CertThumbprintS256: key.X5tS256(),
-			extra:                  extra,
-		},
+		BaseKey: bk,
 		KeyType: key.Kty(),
 	}
 }
@@ -398,3 +373,185 @@ func (key *SymetricKey) IntoPublicKey() crypto.PublicKey {
 func (key *SymetricKey) IntoPrivateKey() crypto.PrivateKey {
 	return key.Key
 }
+
+func (key *OKPPrivateKey) intoUnknown() *UnknownKey {
+	// TODO : Fatal error?
+	bk := key.BaseKey.clone()
+	extra := bk.extra
+	extra["crv"] = curveEd25519
+	extra["x"] = base64.RawURLEncoding.EncodeToString(key.Key.Public().(ed25519.PublicKey))
+	extra["d"] = base64.RawURLEncoding.EncodeToString(key.Key.Seed())
+	return &UnknownKey{
+		BaseKey: bk,
+		KeyType: key.Kty(),
+	}
+}
+
+func (key *OKPPrivateKey) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := EncodeKeyBy(context.Background(), key, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (key *OKPPrivateKey) UnmarshalJSON(bts []byte) error {
+	rdr := bytes.NewReader(bts)
+	var opt *OptionDecodeKey
+	ctx := MustGetOptionFromContext(context.Background(), &opt, true)
+	opt.constraintKeyType = KeyTypeOKP
+	dat, err := DecodeKeyBy(ctx, rdr)
+	if err != nil {
+		return err
+	}
+	*key = *(dat.(*OKPPrivateKey))
+	return nil
+}
+
+func (key *OKPPrivateKey) IntoKey() interface{} {
+	return key.Key
+}
+
+func (key *OKPPrivateKey) IntoPublicKey() crypto.PublicKey {
+	return key.Key.Public()
+}
+
+func (key *OKPPrivateKey) IntoPrivateKey() crypto.PrivateKey {
+	return key.Key
+}
+
+func (key *OKPPublicKey) intoUnknown() *UnknownKey {
+	// TODO : Fatal error?
+	bk := key.BaseKey.clone()
+	extra := bk.extra
+	extra["crv"] = curveEd25519
+	extra["x"] = base64.RawURLEncoding.EncodeToString(key.Key)
+	return &UnknownKey{
+		BaseKey: bk,
+		KeyType: key.Kty(),
+	}
+}
+
+func (key *OKPPublicKey) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := EncodeKeyBy(context.Background(), key, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (key *OKPPublicKey) UnmarshalJSON(bts []byte) error {
+	rdr := bytes.NewReader(bts)
+	var opt *OptionDecodeKey
+	ctx := MustGetOptionFromContext(context.Background(), &opt, true)
+	opt.constraintKeyType = KeyTypeOKP
+	dat, err := DecodeKeyBy(ctx, rdr)
+	if err != nil {
+		return err
+	}
+	*key = *(dat.(*OKPPublicKey))
+	return nil
+}
+
+func (key *OKPPublicKey) IntoKey() interface{} {
+	return key.Key
+}
+
+func (key *OKPPublicKey) IntoPublicKey() crypto.PublicKey {
+	return key.Key
+}
+
+func (key *OKPPublicKey) IntoPrivateKey() crypto.PrivateKey {
+	return nil
+}
+
+func (key *ECDHPrivateKey) intoUnknown() *UnknownKey {
+	// TODO : Fatal error?
+	bk := key.BaseKey.clone()
+	extra := bk.extra
+	extra["crv"] = curveX25519
+	extra["x"] = base64.RawURLEncoding.EncodeToString(key.Key.PublicKey().Bytes())
+	extra["d"] = base64.RawURLEncoding.EncodeToString(key.Key.Bytes())
+	return &UnknownKey{
+		BaseKey: bk,
+		KeyType: key.Kty(),
+	}
+}
+
+func (key *ECDHPrivateKey) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := EncodeKeyBy(context.Background(), key, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (key *ECDHPrivateKey) UnmarshalJSON(bts []byte) error {
+	rdr := bytes.NewReader(bts)
+	var opt *OptionDecodeKey
+	ctx := MustGetOptionFromContext(context.Background(), &opt, true)
+	opt.constraintKeyType = KeyTypeOKP
+	dat, err := DecodeKeyBy(ctx, rdr)
+	if err != nil {
+		return err
+	}
+	*key = *(dat.(*ECDHPrivateKey))
+	return nil
+}
+
+func (key *ECDHPrivateKey) IntoKey() interface{} {
+	return key.Key
+}
+
+func (key *ECDHPrivateKey) IntoPublicKey() crypto.PublicKey {
+	return key.Key.PublicKey()
+}
+
+func (key *ECDHPrivateKey) IntoPrivateKey() crypto.PrivateKey {
+	return key.Key
+}
+
+func (key *ECDHPublicKey) intoUnknown() *UnknownKey {
+	// TODO : Fatal error?
+	bk := key.BaseKey.clone()
+	extra := bk.extra
+	extra["crv"] = curveX25519
+	extra["x"] = base64.RawURLEncoding.EncodeToString(key.Key.Bytes())
+	return &UnknownKey{
+		BaseKey: bk,
+		KeyType: key.Kty(),
+	}
+}
+
+func (key *ECDHPublicKey) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := EncodeKeyBy(context.Background(), key, buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (key *ECDHPublicKey) UnmarshalJSON(bts []byte) error {
+	rdr := bytes.NewReader(bts)
+	var opt *OptionDecodeKey
+	ctx := MustGetOptionFromContext(context.Background(), &opt, true)
+	opt.constraintKeyType = KeyTypeOKP
+	dat, err := DecodeKeyBy(ctx, rdr)
+	if err != nil {
+		return err
+	}
+	*key = *(dat.(*ECDHPublicKey))
+	return nil
+}
+
+func (key *ECDHPublicKey) IntoKey() interface{} {
+	return key.Key
+}
+
+func (key *ECDHPublicKey) IntoPublicKey() crypto.PublicKey {
+	return key.Key
+}
+
+func (key *ECDHPublicKey) IntoPrivateKey() crypto.PrivateKey {
+	return nil
+}
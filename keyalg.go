@@ -1,5 +1,13 @@
 package jwk
 
+import (
+	"crypto"
+	"fmt"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
 type Algorithm string
 
 // https://www.rfc-editor.org/rfc/rfc7518.html#section-7.1.2
@@ -76,56 +84,230 @@ const (
 	AlgorithmA192GCM Algorithm = "A192GCM"
 	// Recommended, AES GCM using 256-bit key
 	AlgorithmA256GCM Algorithm = "A256GCM"
+	// Recommended, EdDSA signature algorithms
+	// https://www.rfc-editor.org/rfc/rfc8037.html#section-3.1
+	AlgorithmEdDSA Algorithm = "EdDSA"
+	// Optional, ECDSA using secp256k1 curve and SHA-256
+	// https://www.rfc-editor.org/rfc/rfc8812.html#section-3.2
+	AlgorithmES256K Algorithm = "ES256K"
+)
+
+// AlgorithmFamily is where algorithm is used, JWS `alg`, JWE `alg` or JWE `enc`
+type AlgorithmFamily string
+
+const (
+	// JWS `alg`, digital signature or MAC
+	// https://www.rfc-editor.org/rfc/rfc7518.html#section-3.1
+	AlgorithmFamilySignature AlgorithmFamily = "JWS"
+	// JWE `alg`, key management
+	// https://www.rfc-editor.org/rfc/rfc7518.html#section-4.1
+	AlgorithmFamilyKeyManagement AlgorithmFamily = "JWE-alg"
+	// JWE `enc`, content encryption
+	// https://www.rfc-editor.org/rfc/rfc7518.html#section-5.1
+	AlgorithmFamilyContentEncryption AlgorithmFamily = "JWE-enc"
+)
+
+// AlgorithmInfo is metadata of algorithm
+type AlgorithmInfo struct {
+	Family AlgorithmFamily
+	// Hash function used by algorithm, 0 if algorithm doesn't use hash directly
+	Hash crypto.Hash
+	// Key types which can be used with algorithm, first one is primary key type
+	KeyTypes []KeyType
+	// `crv` values which can be used with algorithm, empty if algorithm is not for `EC` or `OKP` key
+	Curves []string
+	// Key size in bits, 0 if there is no constraint
+	// For `EC`, it is size of curve, for `oct`, it is length of `k`, for `RSA`, it is size of modulus
+	KeySize int
+	// If true, key size must be exactly `KeySize`, otherwise `KeySize` is minimum
+	ExactKeySize bool
+	// Recommended `use` of key
+	KeyUse KeyUse
+	// Recommended `key_ops` of key
+	KeyOps []KeyOp
+}
+
+var (
+	opsSig    = []KeyOp{KeyOpSign, KeyOpVerify}
+	opsWrap   = []KeyOp{KeyOpWrapKey, KeyOpUnwrapKey}
+	opsDerive = []KeyOp{KeyOpDeriveKey}
+	opsEnc    = []KeyOp{KeyOpEncrypt, KeyOpDecrypt}
+
+	curvesECDH = []string{"P-256", "P-384", "P-521", curveX25519, curveX448}
+)
+
+var (
+	algMutex sync.RWMutex
+	// algorithms registered by `RegisterAlgorithm` and `RegisterCurve`, in registration order
+	algRegistered []Algorithm
 )
 
-var _ALG_TABLE = map[Algorithm]KeyType{
-	AlgorithmHS256:         KeyTypeOctet,
-	AlgorithmHS384:         KeyTypeOctet,
-	AlgorithmHS512:         KeyTypeOctet,
-	AlgorithmRS256:         KeyTypeRSA,
-	AlgorithmRS384:         KeyTypeRSA,
-	AlgorithmRS512:         KeyTypeRSA,
-	AlgorithmES256:         KeyTypeEC,
-	AlgorithmES384:         KeyTypeEC,
-	AlgorithmES512:         KeyTypeEC,
-	AlgorithmPS256:         KeyTypeRSA,
-	AlgorithmPS384:         KeyTypeRSA,
-	AlgorithmPS512:         KeyTypeRSA,
-	AlgorithmRSA1_5:        KeyTypeRSA,
-	AlgorithmRSAOAEP:       KeyTypeRSA,
-	AlgorithmRSAOAEP256:    KeyTypeRSA,
-	AlgorithmA128KW:        KeyTypeOctet,
-	AlgorithmA192KW:        KeyTypeOctet,
-	AlgorithmA256KW:        KeyTypeOctet,
-	AlgorithmECDHES:        KeyTypeEC,
-	AlgorithmECDHES_A128KW: KeyTypeEC,
-	AlgorithmECDHES_A192KW: KeyTypeEC,
-	AlgorithmECDHES_A256KW: KeyTypeEC,
-	AlgorithmA128GCMKW:     KeyTypeOctet,
-	AlgorithmA192GCMKW:     KeyTypeOctet,
-	AlgorithmA256GCMKW:     KeyTypeOctet,
-	AlgorithmNone:          "",
-	// TODO : what is that?
-	// AlgorithmDir
-	// AlgorithmPBES2_HS256_A128KW
-	// AlgorithmPBES2_HS384_A192KW
-	// AlgorithmPBES2_HS512_A256KW
-	// AlgorithmA128CBC_HS256
-	// AlgorithmA192CBC_HS384
-	// AlgorithmA256CBC_HS512
-	// AlgorithmA128GCM
-	// AlgorithmA192GCM
-	// AlgorithmA256GCM
+var _ALG_TABLE = map[Algorithm]*AlgorithmInfo{
+	AlgorithmHS256:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 256, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmHS384:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA384, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 384, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmHS512:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA512, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 512, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmRS256:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmRS384:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA384, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmRS512:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA512, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmES256:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeEC}, Curves: []string{"P-256"}, KeySize: 256, ExactKeySize: true, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmES384:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA384, KeyTypes: []KeyType{KeyTypeEC}, Curves: []string{"P-384"}, KeySize: 384, ExactKeySize: true, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmES512:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA512, KeyTypes: []KeyType{KeyTypeEC}, Curves: []string{"P-521"}, KeySize: 521, ExactKeySize: true, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmES256K:             {Family: AlgorithmFamilySignature, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeEC}, Curves: []string{curveSecp256k1}, KeySize: 256, ExactKeySize: true, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmPS256:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmPS384:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA384, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmPS512:              {Family: AlgorithmFamilySignature, Hash: crypto.SHA512, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmEdDSA:              {Family: AlgorithmFamilySignature, KeyTypes: []KeyType{KeyTypeOKP}, Curves: []string{curveEd25519, curveEd448}, KeyUse: KeyUseSig, KeyOps: opsSig},
+	AlgorithmNone:               {Family: AlgorithmFamilySignature, KeyTypes: []KeyType{}},
+	AlgorithmRSA1_5:             {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmRSAOAEP:            {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA1, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmRSAOAEP256:         {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeRSA}, KeySize: 2048, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmA128KW:             {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 128, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmA192KW:             {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 192, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmA256KW:             {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 256, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmDir:                {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeOctet}, KeyUse: KeyUseEnc, KeyOps: opsEnc},
+	AlgorithmECDHES:             {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeEC, KeyTypeOKP}, Curves: curvesECDH, KeyUse: KeyUseEnc, KeyOps: opsDerive},
+	AlgorithmECDHES_A128KW:      {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeEC, KeyTypeOKP}, Curves: curvesECDH, KeyUse: KeyUseEnc, KeyOps: opsDerive},
+	AlgorithmECDHES_A192KW:      {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeEC, KeyTypeOKP}, Curves: curvesECDH, KeyUse: KeyUseEnc, KeyOps: opsDerive},
+	AlgorithmECDHES_A256KW:      {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeEC, KeyTypeOKP}, Curves: curvesECDH, KeyUse: KeyUseEnc, KeyOps: opsDerive},
+	AlgorithmA128GCMKW:          {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 128, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmA192GCMKW:          {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 192, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmA256GCMKW:          {Family: AlgorithmFamilyKeyManagement, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 256, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmPBES2_HS256_A128KW: {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeOctet}, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmPBES2_HS384_A192KW: {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA384, KeyTypes: []KeyType{KeyTypeOctet}, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmPBES2_HS512_A256KW: {Family: AlgorithmFamilyKeyManagement, Hash: crypto.SHA512, KeyTypes: []KeyType{KeyTypeOctet}, KeyUse: KeyUseEnc, KeyOps: opsWrap},
+	AlgorithmA128CBC_HS256:      {Family: AlgorithmFamilyContentEncryption, Hash: crypto.SHA256, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 256, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsEnc},
+	AlgorithmA192CBC_HS384:      {Family: AlgorithmFamilyContentEncryption, Hash: crypto.SHA384, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 384, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsEnc},
+	AlgorithmA256CBC_HS512:      {Family: AlgorithmFamilyContentEncryption, Hash: crypto.SHA512, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 512, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsEnc},
+	AlgorithmA128GCM:            {Family: AlgorithmFamilyContentEncryption, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 128, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsEnc},
+	AlgorithmA192GCM:            {Family: AlgorithmFamilyContentEncryption, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 192, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsEnc},
+	AlgorithmA256GCM:            {Family: AlgorithmFamilyContentEncryption, KeyTypes: []KeyType{KeyTypeOctet}, KeySize: 256, ExactKeySize: true, KeyUse: KeyUseEnc, KeyOps: opsEnc},
+}
+
+// RegisterAlgorithm make `alg` known algorithm, it is used by `IsKnown`, `IntoKeyType`, `GuessAlgorithm` and decode options
+// If `method` is not nil, it is used by `LetSigningMethod` and registered to golang-jwt/jwt, `method.Alg()` must be same as `alg`
+// Builtin algorithms can't be registered, if there is already registered algorithm with same name, it overwrite
+func RegisterAlgorithm(alg Algorithm, info AlgorithmInfo, method jwt.SigningMethod) error {
+	if !alg.Exist() || isBuiltinAlgorithm(alg) {
+		return makeErrors(ErrParameter, fmt.Errorf("can't register alg='%s'", alg))
+	}
+	if method != nil && method.Alg() != string(alg) {
+		return makeErrors(ErrParameter, fmt.Errorf("method.Alg()='%s' is not same as alg='%s'", method.Alg(), alg))
+	}
+	registerAlgorithm(alg, &info)
+	if method != nil {
+		algMutex.Lock()
+		signingMethodTable[alg] = method
+		algMutex.Unlock()
+		jwt.RegisterSigningMethod(method.Alg(), func() jwt.SigningMethod { return method })
+	}
+	return nil
+}
+
+// isBuiltinAlgorithm return true when `alg` is known but not registered by `RegisterAlgorithm` or `RegisterCurve`
+func isBuiltinAlgorithm(alg Algorithm) bool {
+	algMutex.RLock()
+	defer algMutex.RUnlock()
+	if _, ok := _ALG_TABLE[alg]; !ok {
+		return false
+	}
+	for _, registered := range algRegistered {
+		if registered == alg {
+			return false
+		}
+	}
+	return true
+}
+
+func registerAlgorithm(alg Algorithm, info *AlgorithmInfo) {
+	info.KeyTypes = append([]KeyType{}, info.KeyTypes...)
+	info.Curves = append([]string(nil), info.Curves...)
+	info.KeyOps = append([]KeyOp(nil), info.KeyOps...)
+	algMutex.Lock()
+	defer algMutex.Unlock()
+	if _, ok := _ALG_TABLE[alg]; !ok {
+		algRegistered = append(algRegistered, alg)
+	}
+	_ALG_TABLE[alg] = info
+}
+
+func lookupAlgorithm(alg Algorithm) *AlgorithmInfo {
+	algMutex.RLock()
+	defer algMutex.RUnlock()
+	return _ALG_TABLE[alg]
+}
+
+// registeredAlgorithms return algorithms registered by `RegisterAlgorithm` and `RegisterCurve`, in registration order
+func registeredAlgorithms() []Algorithm {
+	algMutex.RLock()
+	defer algMutex.RUnlock()
+	return append([]Algorithm(nil), algRegistered...)
+}
+
+func lookupSigningMethod(alg Algorithm) jwt.SigningMethod {
+	algMutex.RLock()
+	defer algMutex.RUnlock()
+	return signingMethodTable[alg]
+}
+
+// Info return metadata of algorithm, if algorithm is not known, it return false
+func (alg Algorithm) Info() (AlgorithmInfo, bool) {
+	info := lookupAlgorithm(alg)
+	if info == nil {
+		return AlgorithmInfo{}, false
+	}
+	res := *info
+	res.KeyTypes = append([]KeyType{}, info.KeyTypes...)
+	res.Curves = append([]string(nil), info.Curves...)
+	res.KeyOps = append([]KeyOp(nil), info.KeyOps...)
+	return res, true
 }
 
 func (alg Algorithm) IsKnown() bool {
-	_, ok := _ALG_TABLE[alg]
-	return ok
+	return lookupAlgorithm(alg) != nil
 }
 func (alg Algorithm) Exist() bool {
 	return len(alg) > 0
 }
 
+// IntoKeyType return primary key type of algorithm
+// If algorithm can be used with several key types, for example `ECDH-ES` with `EC` and `OKP`, use `IntoKeyTypes`
 func (alg Algorithm) IntoKeyType() KeyType {
-	return _ALG_TABLE[alg]
+	if info := lookupAlgorithm(alg); info != nil && len(info.KeyTypes) > 0 {
+		return info.KeyTypes[0]
+	}
+	return ""
+}
+
+// IntoKeyTypes return every key types which can be used with algorithm
+func (alg Algorithm) IntoKeyTypes() []KeyType {
+	info := lookupAlgorithm(alg)
+	if info == nil {
+		return []KeyType{}
+	}
+	res := make([]KeyType, len(info.KeyTypes))
+	copy(res, info.KeyTypes)
+	return res
+}
+
+func (alg Algorithm) allowKeyType(kty KeyType) bool {
+	info := lookupAlgorithm(alg)
+	if info == nil {
+		return false
+	}
+	for _, k := range info.KeyTypes {
+		if k == kty {
+			return true
+		}
+	}
+	return false
+}
+
+// symetricKeySize return size of `oct` key for algorithm in bytes, if isMinimum is true, key can be larger than size
+// If algorithm is not for `oct` key, it return 0
+func symetricKeySize(alg Algorithm) (size int, isMinimum bool) {
+	info := lookupAlgorithm(alg)
+	if info == nil || info.KeySize == 0 || !alg.allowKeyType(KeyTypeOctet) {
+		return 0, false
+	}
+	return info.KeySize / 8, !info.ExactKeySize
 }
@@ -0,0 +1,203 @@
+package jwk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var allAlgorithms = []jwk.Algorithm{
+	jwk.AlgorithmHS256, jwk.AlgorithmHS384, jwk.AlgorithmHS512,
+	jwk.AlgorithmRS256, jwk.AlgorithmRS384, jwk.AlgorithmRS512,
+	jwk.AlgorithmES256, jwk.AlgorithmES384, jwk.AlgorithmES512,
+	jwk.AlgorithmPS256, jwk.AlgorithmPS384, jwk.AlgorithmPS512,
+	jwk.AlgorithmNone,
+	jwk.AlgorithmRSA1_5, jwk.AlgorithmRSAOAEP, jwk.AlgorithmRSAOAEP256,
+	jwk.AlgorithmA128KW, jwk.AlgorithmA192KW, jwk.AlgorithmA256KW,
+	jwk.AlgorithmDir,
+	jwk.AlgorithmECDHES, jwk.AlgorithmECDHES_A128KW, jwk.AlgorithmECDHES_A192KW, jwk.AlgorithmECDHES_A256KW,
+	jwk.AlgorithmA128GCMKW, jwk.AlgorithmA192GCMKW, jwk.AlgorithmA256GCMKW,
+	jwk.AlgorithmPBES2_HS256_A128KW, jwk.AlgorithmPBES2_HS384_A192KW, jwk.AlgorithmPBES2_HS512_A256KW,
+	jwk.AlgorithmA128CBC_HS256, jwk.AlgorithmA192CBC_HS384, jwk.AlgorithmA256CBC_HS512,
+	jwk.AlgorithmA128GCM, jwk.AlgorithmA192GCM, jwk.AlgorithmA256GCM,
+	jwk.AlgorithmEdDSA, jwk.AlgorithmES256K,
+}
+
+func TestAlgorithmInfo(t *testing.T) {
+	t.Run("every algorithm", func(t *testing.T) {
+		for _, alg := range allAlgorithms {
+			info, ok := alg.Info()
+			if !ok {
+				t.Fatalf("expected %v has info, but not", alg)
+			}
+			if !alg.IsKnown() {
+				t.Fatalf("expected %v is known, but not", alg)
+			}
+			if alg != jwk.AlgorithmNone && len(info.KeyTypes) == 0 {
+				t.Fatalf("expected %v has key types, but not", alg)
+			}
+		}
+	})
+	t.Run("unknown", func(t *testing.T) {
+		if _, ok := jwk.Algorithm("unknown").Info(); ok {
+			t.Fatalf("expected false, but got true")
+		}
+	})
+	t.Run("family", func(t *testing.T) {
+		for alg, family := range map[jwk.Algorithm]jwk.AlgorithmFamily{
+			jwk.AlgorithmHS256:              jwk.AlgorithmFamilySignature,
+			jwk.AlgorithmNone:               jwk.AlgorithmFamilySignature,
+			jwk.AlgorithmDir:                jwk.AlgorithmFamilyKeyManagement,
+			jwk.AlgorithmPBES2_HS256_A128KW: jwk.AlgorithmFamilyKeyManagement,
+			jwk.AlgorithmA128CBC_HS256:      jwk.AlgorithmFamilyContentEncryption,
+			jwk.AlgorithmA256GCM:            jwk.AlgorithmFamilyContentEncryption,
+		} {
+			if info, _ := alg.Info(); info.Family != family {
+				t.Fatalf("expected %v is %v, but got %v", alg, family, info.Family)
+			}
+		}
+	})
+	t.Run("ES512", func(t *testing.T) {
+		info, _ := jwk.AlgorithmES512.Info()
+		if info.Hash != crypto.SHA512 {
+			t.Fatalf("expected %v, but got %v", crypto.SHA512, info.Hash)
+		}
+		if len(info.Curves) != 1 || info.Curves[0] != "P-521" {
+			t.Fatalf("expected [P-521], but got %v", info.Curves)
+		}
+		if info.KeySize != 521 || !info.ExactKeySize {
+			t.Fatalf("expected exact 521, but got %v, %v", info.KeySize, info.ExactKeySize)
+		}
+		if info.KeyUse != jwk.KeyUseSig {
+			t.Fatalf("expected %v, but got %v", jwk.KeyUseSig, info.KeyUse)
+		}
+	})
+	t.Run("HS256", func(t *testing.T) {
+		info, _ := jwk.AlgorithmHS256.Info()
+		if info.KeySize != 256 || info.ExactKeySize {
+			t.Fatalf("expected minimum 256, but got %v, %v", info.KeySize, info.ExactKeySize)
+		}
+	})
+	t.Run("ECDH-ES", func(t *testing.T) {
+		info, _ := jwk.AlgorithmECDHES.Info()
+		if len(info.KeyTypes) != 2 || info.KeyTypes[0] != jwk.KeyTypeEC || info.KeyTypes[1] != jwk.KeyTypeOKP {
+			t.Fatalf("expected [EC OKP], but got %v", info.KeyTypes)
+		}
+		if len(info.KeyOps) != 1 || info.KeyOps[0] != jwk.KeyOpDeriveKey {
+			t.Fatalf("expected [deriveKey], but got %v", info.KeyOps)
+		}
+	})
+	t.Run("copy", func(t *testing.T) {
+		info, _ := jwk.AlgorithmRS256.Info()
+		info.KeyTypes[0] = jwk.KeyTypeEC
+		info.KeyOps[0] = jwk.KeyOpDecrypt
+		if jwk.AlgorithmRS256.IntoKeyType() != jwk.KeyTypeRSA {
+			t.Fatalf("expected %v, but got %v", jwk.KeyTypeRSA, jwk.AlgorithmRS256.IntoKeyType())
+		}
+		if again, _ := jwk.AlgorithmRS256.Info(); again.KeyOps[0] != jwk.KeyOpSign {
+			t.Fatalf("expected %v, but got %v", jwk.KeyOpSign, again.KeyOps[0])
+		}
+	})
+}
+
+func TestRegisterAlgorithm(t *testing.T) {
+	const AlgorithmHS256V jwk.Algorithm = "HS256V"
+	err := jwk.RegisterAlgorithm(AlgorithmHS256V, jwk.AlgorithmInfo{
+		Family:   jwk.AlgorithmFamilySignature,
+		Hash:     crypto.SHA256,
+		KeyTypes: []jwk.KeyType{jwk.KeyTypeOctet},
+		KeySize:  256,
+		KeyUse:   jwk.KeyUseSig,
+		KeyOps:   []jwk.KeyOp{jwk.KeyOpSign, jwk.KeyOpVerify},
+	}, &jwt.SigningMethodHMAC{Name: string(AlgorithmHS256V), Hash: crypto.SHA256})
+	if err != nil {
+		t.Fatalf("expected <nil>, but got %v", err)
+	}
+	t.Run("builtin", func(t *testing.T) {
+		err := jwk.RegisterAlgorithm(jwk.AlgorithmRS256, jwk.AlgorithmInfo{Family: jwk.AlgorithmFamilySignature}, jwt.SigningMethodPS256)
+		if !errors.Is(err, jwk.ErrParameter) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrParameter)
+		}
+		if jwt.GetSigningMethod(string(jwk.AlgorithmRS256)) != jwt.SigningMethodRS256 {
+			t.Fatalf("expected %v is not changed, but changed", jwk.AlgorithmRS256)
+		}
+		if info, _ := jwk.AlgorithmRS256.Info(); info.KeyTypes[0] != jwk.KeyTypeRSA {
+			t.Fatalf("expected %v is not changed, but changed", jwk.AlgorithmRS256)
+		}
+	})
+	t.Run("mismatched method", func(t *testing.T) {
+		const AlgorithmMismatch jwk.Algorithm = "HS256-mismatch"
+		err := jwk.RegisterAlgorithm(AlgorithmMismatch, jwk.AlgorithmInfo{Family: jwk.AlgorithmFamilySignature}, &jwt.SigningMethodHMAC{Name: "HS256-other", Hash: crypto.SHA256})
+		if !errors.Is(err, jwk.ErrParameter) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrParameter)
+		}
+		if AlgorithmMismatch.IsKnown() || jwt.GetSigningMethod("HS256-other") != nil {
+			t.Fatalf("expected not registered, but registered")
+		}
+	})
+	t.Run("known", func(t *testing.T) {
+		if !AlgorithmHS256V.IsKnown() {
+			t.Fatalf("expected true, but got false")
+		}
+		if AlgorithmHS256V.IntoKeyType() != jwk.KeyTypeOctet {
+			t.Fatalf("expected %v, but got %v", jwk.KeyTypeOctet, AlgorithmHS256V.IntoKeyType())
+		}
+	})
+	t.Run("decode", func(t *testing.T) {
+		disallow := jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.DisallowUnknownAlgorithm = true })
+		if _, err := jwk.DecodeKey(strings.NewReader(`{"kty":"oct","alg":"HS256V","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`), disallow); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		_, err := jwk.DecodeKey(strings.NewReader(`{"kty":"oct","alg":"HS256V","k":"AyM1SysPpbyDfgZld3umjw"}`), disallow)
+		if !errors.Is(err, jwk.ErrInsufficientKeySize) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrInsufficientKeySize)
+		}
+	})
+	t.Run("sign", func(t *testing.T) {
+		k, err := jwk.NewKey([]byte("0123456789abcdef0123456789abcdef"), AlgorithmHS256V)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if m := jwk.LetSigningMethod(k); m == nil || m.Alg() != string(AlgorithmHS256V) {
+			t.Fatalf("expected %v, but got %v", AlgorithmHS256V, m)
+		}
+		checkJWT(t, k)
+	})
+	t.Run("incompatible key type", func(t *testing.T) {
+		if _, err := jwk.NewKey(mustECDSA(elliptic.P256()), AlgorithmHS256V); !errors.Is(err, jwk.ErrIncompatibleAlgorithm) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrIncompatibleAlgorithm)
+		}
+	})
+	t.Run("guess by curve", func(t *testing.T) {
+		const AlgorithmESTest jwk.Algorithm = "ESTEST"
+		// *elliptic.CurveParams is distinct curve from elliptic.P256()
+		curve := elliptic.P256().Params()
+		jwk.RegisterCurve("P-256-test", curve, "")
+		err := jwk.RegisterAlgorithm(AlgorithmESTest, jwk.AlgorithmInfo{
+			Family:   jwk.AlgorithmFamilySignature,
+			KeyTypes: []jwk.KeyType{jwk.KeyTypeEC},
+			Curves:   []string{"P-256-test"},
+		}, nil)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		prik, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		k, err := jwk.NewKey(prik)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if alg := jwk.GuessAlgorithm(k); alg != AlgorithmESTest {
+			t.Fatalf("expected %v, but got %v", AlgorithmESTest, alg)
+		}
+	})
+}
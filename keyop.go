@@ -1,7 +1,21 @@
 package jwk
 
+import "sort"
+
 type KeyOps map[KeyOp]struct{}
 
+// KeyOpsOrder decide order of encoded `key_ops`
+type KeyOpsOrder uint8
+
+const (
+	// KeyOpsUnordered doesn't guarantee any order
+	KeyOpsUnordered KeyOpsOrder = iota
+	// KeyOpsSorted sort ops lexicographically
+	KeyOpsSorted
+	// KeyOpsDeclared keep order of decoded `key_ops`, ops added after decoding are sorted after them
+	KeyOpsDeclared
+)
+
 type KeyOp string
 
 // https://datatracker.ietf.org/doc/html/rfc7517#section-8.3.2
@@ -62,18 +76,54 @@ func (ops KeyOps) IsValidCombination() bool {
 	}
 	return false
 }
+
+// Compatible report whether every known op is allowed by `use`, `sig` allow `sign` and `verify`, `enc` allow others
+// If `use` is empty or unknown, it can't decide, so it return true
+// https://www.rfc-editor.org/rfc/rfc7517#section-4.3
 func (ops KeyOps) Compatible(use KeyUse) bool {
-	// TODO : Is that right?
-	var trg KeyOp
-	switch use {
-	case KeyUseEnc:
-		trg = KeyOpUnwrapKey
-	case KeyUseSig:
-		trg = KeyOpVerify
-	}
-	_, ok := ops[trg]
-	return ok
+	if !use.IsKnown() {
+		return true
+	}
+	for op := range ops {
+		if op.IsKnown() && op.intoUse() != use {
+			return false
+		}
+	}
+	return true
 }
+
+// Sorted return ops sorted lexicographically
+func (ops KeyOps) Sorted() []KeyOp {
+	slc := ops.AsSlice()
+	sort.Slice(slc, func(i, j int) bool { return slc[i] < slc[j] })
+	return slc
+}
+
+// keyOpsSlice return `key_ops` of key in order
+func keyOpsSlice(bk *BaseKey, order KeyOpsOrder) []KeyOp {
+	switch order {
+	case KeyOpsSorted:
+		return bk.KeyOperations.Sorted()
+	case KeyOpsDeclared:
+		slc := make([]KeyOp, 0, len(bk.KeyOperations))
+		seen := make(map[KeyOp]struct{}, len(bk.KeyOperations))
+		for _, op := range bk.keyOpsOrder {
+			if _, ok := seen[op]; !ok && bk.KeyOperations.In(op) {
+				seen[op] = struct{}{}
+				slc = append(slc, op)
+			}
+		}
+		for _, op := range bk.KeyOperations.Sorted() {
+			if _, ok := seen[op]; !ok {
+				slc = append(slc, op)
+			}
+		}
+		return slc
+	default:
+		return bk.KeyOperations.AsSlice()
+	}
+}
+
 func (ops KeyOps) AsSlice() []KeyOp {
 	slc := make([]KeyOp, 0, len(ops))
 	for k := range ops {
@@ -82,6 +132,40 @@ func (ops KeyOps) AsSlice() []KeyOp {
 	return slc
 }
 
+// intoUse return `use` which allow op, "" if op is unknown
+func (op KeyOp) intoUse() KeyUse {
+	switch op {
+	case KeyOpSign, KeyOpVerify:
+		return KeyUseSig
+	case KeyOpEncrypt, KeyOpDecrypt, KeyOpWrapKey, KeyOpUnwrapKey, KeyOpDeriveKey, KeyOpDeriveBits:
+		return KeyUseEnc
+	}
+	return ""
+}
+
+// Permits report whether key can be used for op, by `use` and `key_ops`
+// Key without both permits every op, key with both permits op only when each of them permits
+// Key with unknown `use` permits nothing
+// https://www.rfc-editor.org/rfc/rfc7517#section-4.3
+func (key *BaseKey) Permits(op KeyOp) bool {
+	if key.KeyUse.Exist() && op.intoUse() != key.KeyUse {
+		return false
+	}
+	if len(key.KeyOperations) > 0 && !key.KeyOperations.In(op) {
+		return false
+	}
+	return true
+}
+
+// checkPermits return `*OperationError` when key can't be used for op
+// Every helper using key for some operation must check it
+func checkPermits(key Key, op KeyOp) error {
+	if key.Permits(op) {
+		return nil
+	}
+	return &OperationError{Op: op, Use: key.Use(), KeyOps: key.KeyOps().AsSlice()}
+}
+
 func (op KeyOp) IsKnown() bool {
 	switch op {
 	case KeyOpSign:
@@ -0,0 +1,125 @@
+package jwk_test
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var allKeyOps = []jwk.KeyOp{
+	jwk.KeyOpSign, jwk.KeyOpVerify,
+	jwk.KeyOpEncrypt, jwk.KeyOpDecrypt,
+	jwk.KeyOpWrapKey, jwk.KeyOpUnwrapKey,
+	jwk.KeyOpDeriveKey, jwk.KeyOpDeriveBits,
+}
+
+func TestKeyOpsCompatible(t *testing.T) {
+	for _, a := range allKeyOps {
+		for _, b := range allKeyOps {
+			ops := jwk.KeyOps{a: {}, b: {}}
+			sig := (a == jwk.KeyOpSign || a == jwk.KeyOpVerify) && (b == jwk.KeyOpSign || b == jwk.KeyOpVerify)
+			enc := a != jwk.KeyOpSign && a != jwk.KeyOpVerify && b != jwk.KeyOpSign && b != jwk.KeyOpVerify
+			if ops.Compatible(jwk.KeyUseSig) != sig {
+				t.Fatalf("expected %v for use=sig, key_ops=[%v %v], but got %v", sig, a, b, !sig)
+			}
+			if ops.Compatible(jwk.KeyUseEnc) != enc {
+				t.Fatalf("expected %v for use=enc, key_ops=[%v %v], but got %v", enc, a, b, !enc)
+			}
+			if !ops.Compatible("") {
+				t.Fatalf("expected true for empty use, but got false")
+			}
+		}
+	}
+}
+
+func TestPermits(t *testing.T) {
+	for name, tc := range map[string]struct {
+		json      string
+		permitted []jwk.KeyOp
+	}{
+		"nothing":      {`{"kty":"oct","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`, allKeyOps},
+		"use sig":      {`{"kty":"oct","use":"sig","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`, []jwk.KeyOp{jwk.KeyOpSign, jwk.KeyOpVerify}},
+		"use enc":      {`{"kty":"oct","use":"enc","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`, allKeyOps[2:]},
+		"use unknown":  {`{"kty":"oct","use":"unknown","k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`, nil},
+		"key_ops":      {`{"kty":"oct","key_ops":["verify"],"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`, []jwk.KeyOp{jwk.KeyOpVerify}},
+		"use, key_ops": {`{"kty":"oct","use":"enc","key_ops":["wrapKey","unwrapKey"],"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr8"}`, []jwk.KeyOp{jwk.KeyOpWrapKey, jwk.KeyOpUnwrapKey}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			k, err := jwk.DecodeKey(strings.NewReader(tc.json))
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			permitted := jwk.KeyOps{}
+			for _, op := range tc.permitted {
+				permitted[op] = struct{}{}
+			}
+			for _, op := range allKeyOps {
+				if k.Permits(op) != permitted.In(op) {
+					t.Fatalf("expected %v for %v, but got %v", permitted.In(op), op, k.Permits(op))
+				}
+			}
+		})
+	}
+}
+
+func TestForbiddenOperation(t *testing.T) {
+	t.Run("sign", func(t *testing.T) {
+		k := jwk.MustKey(mustRSA(), jwk.AlgorithmRS256)
+		k.(*jwk.RSAPrivateKey).KeyUse = jwk.KeyUseEnc
+		_, _, err := jwk.LetSign(k, jwt.MapClaims{"hello": "world"})
+		if !errors.Is(err, jwk.ErrForbiddenOperation) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrForbiddenOperation)
+		}
+		var oe *jwk.OperationError
+		if !errors.As(err, &oe) {
+			t.Fatalf("expected %v as %T, but not", err, oe)
+		}
+		if oe.Op != jwk.KeyOpSign || oe.Use != jwk.KeyUseEnc {
+			t.Fatalf("expected %v, %v, but got %v, %v", jwk.KeyOpSign, jwk.KeyUseEnc, oe.Op, oe.Use)
+		}
+	})
+	t.Run("sign without signing method", func(t *testing.T) {
+		x25519, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		for _, k := range []jwk.Key{jwk.MustKey(x25519), jwk.MustKey(mustRSA(), jwk.AlgorithmRSAOAEP)} {
+			_, _, err := jwk.LetSign(k, jwt.MapClaims{"hello": "world"})
+			if !errors.Is(err, jwk.ErrIncompatibleAlgorithm) {
+				t.Fatalf("expected %v is %v, but not", err, jwk.ErrIncompatibleAlgorithm)
+			}
+		}
+	})
+	t.Run("verify", func(t *testing.T) {
+		k := jwk.MustKey(mustRSA(), jwk.AlgorithmRS256)
+		_, signed, err := jwk.LetSign(k, jwt.MapClaims{"hello": "world"})
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		k.(*jwk.RSAPrivateKey).KeyOperations[jwk.KeyOpSign] = struct{}{}
+		_, err = jwk.LetVerify(signed, k, &jwt.MapClaims{})
+		if !errors.Is(err, jwk.ErrForbiddenOperation) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrForbiddenOperation)
+		}
+	})
+	t.Run("verify without guess", func(t *testing.T) {
+		k := jwk.MustKey(mustRSA(), jwk.AlgorithmRS256, jwk.WithKeyID("a"))
+		token := jwt.NewWithClaims(jwk.LetSigningMethod(k), jwt.MapClaims{"hello": "world"})
+		token.Header["kid"] = k.Kid()
+		signed, err := token.SignedString(k.IntoPrivateKey())
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		k.(*jwk.RSAPrivateKey).KeyUse = jwk.KeyUseEnc
+		set := &jwk.Set{Keys: []jwk.Key{k}}
+		_, err = jwt.Parse(signed, jwk.NewJWTVerifierFromSet(set, jwk.WithGuess(false)).Keyfunc)
+		if !errors.Is(err, jwk.ErrForbiddenOperation) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrForbiddenOperation)
+		}
+	})
+}
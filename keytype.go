@@ -10,4 +10,21 @@ const (
 	KeyTypeRSA KeyType = "RSA"
 	// Required, Octet Sequence
 	KeyTypeOctet KeyType = "oct"
+	// Optional, Octet Key Pair
+	// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+	KeyTypeOKP KeyType = "OKP"
+)
+
+// https://www.rfc-editor.org/rfc/rfc8812.html#section-3.1
+const (
+	curveSecp256k1 = "secp256k1"
+)
+
+// https://www.rfc-editor.org/rfc/rfc8037.html#section-2
+const (
+	curveEd25519 = "Ed25519"
+	curveX25519  = "X25519"
+	// Ed448 and X448 are not supported, they are decoded as `*UnknownKey`
+	curveEd448 = "Ed448"
+	curveX448  = "X448"
 )
@@ -0,0 +1,350 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+)
+
+// ParseDER make key from DER bytes, it can be one of
+//   - PKCS #1 RSA private key, RSA public key
+//   - PKCS #8 private key
+//   - SEC 1 EC private key
+//   - PKIX public key
+//   - X.509 certificate, `x5c`, `x5t`, `x5t#S256` is filled by certificate
+func ParseDER(der []byte, options ...OptionalNewKey) (Key, error) {
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		return newCertKey(cert.PublicKey, []*x509.Certificate{cert}, options...)
+	}
+	gokey, err := parseDERKey(der)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(gokey, options...)
+}
+
+// ParsePEM make key from first key in PEM
+// If there are certificates for that key, `x5c`, `x5t`, `x5t#S256` is filled by certificates
+// See `ParsePEMSet` for how certificates are handled
+func ParsePEM(data []byte, options ...OptionalNewKey) (Key, error) {
+	set, err := ParsePEMSet(data, options...)
+	if err != nil {
+		return nil, err
+	}
+	return set.Keys[0], nil
+}
+
+// ParsePEMSet make set from every key in PEM, block type must be one of
+//   - `RSA PRIVATE KEY`, PKCS #1
+//   - `RSA PUBLIC KEY`, PKCS #1
+//   - `PRIVATE KEY`, PKCS #8
+//   - `EC PRIVATE KEY`, SEC 1
+//   - `PUBLIC KEY`, PKIX
+//   - `CERTIFICATE`, X.509
+//
+// Other block types, like `EC PARAMETERS`, are ignored
+// Consecutive certificates which issued by next one are treated as one certificate chain, leaf first
+// If there is key for leaf certificate of chain, chain is used for that key, or else new public key is made from leaf certificate
+func ParsePEMSet(data []byte, options ...OptionalNewKey) (*Set, error) {
+	var entries []*pemEntry
+	for i := 0; ; i++ {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, makeErrors(ErrInvalidPEM, IndexError(i), ErrInvalidX509, err)
+			}
+			if len(entries) > 0 {
+				if last := entries[len(entries)-1]; last.gokey == nil && bytes.Equal(last.chain[len(last.chain)-1].RawIssuer, cert.RawSubject) {
+					last.chain = append(last.chain, cert)
+					continue
+				}
+			}
+			entries = append(entries, &pemEntry{chain: []*x509.Certificate{cert}})
+		case "RSA PRIVATE KEY", "RSA PUBLIC KEY", "PRIVATE KEY", "EC PRIVATE KEY", "PUBLIC KEY":
+			gokey, err := parsePEMBlock(block)
+			if err != nil {
+				return nil, makeErrors(ErrInvalidPEM, IndexError(i), err)
+			}
+			entries = append(entries, &pemEntry{gokey: gokey})
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, makeErrors(ErrInvalidPEM, IndexError(i), fmt.Errorf("encrypted private key is not supported"))
+		}
+	}
+	if len(entries) == 0 {
+		return nil, makeErrors(ErrInvalidPEM, ErrNotExist)
+	}
+	// attach chain to key for leaf certificate
+	for _, chainEntry := range entries {
+		if chainEntry.gokey != nil {
+			continue
+		}
+		leaf := chainEntry.chain[0]
+		for _, keyEntry := range entries {
+			if keyEntry.gokey != nil && keyEntry.chain == nil && isSamePublicKey(keyEntry.gokey, leaf.PublicKey) {
+				keyEntry.chain = chainEntry.chain
+				chainEntry.chain = nil
+				break
+			}
+		}
+	}
+	set := NewSet()
+	for i, entry := range entries {
+		if entry.gokey == nil && entry.chain == nil {
+			continue
+		}
+		if entry.gokey == nil {
+			entry.gokey = entry.chain[0].PublicKey
+		}
+		key, err := newCertKey(entry.gokey, entry.chain, options...)
+		if err != nil {
+			return nil, makeErrors(ErrInnerKey, IndexError(i), err)
+		}
+		set.Keys = append(set.Keys, key)
+	}
+	return set, nil
+}
+
+type pemEntry struct {
+	gokey interface{}
+	chain []*x509.Certificate
+}
+
+func parsePEMBlock(block *pem.Block) (interface{}, error) {
+	var (
+		gokey interface{}
+		err   error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		gokey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		gokey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PRIVATE KEY":
+		gokey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		gokey, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		gokey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, makeErrors(ErrInvalidX509, FieldError(block.Type), err)
+	}
+	return gokey, nil
+}
+
+func parseDERKey(der []byte) (interface{}, error) {
+	if gokey, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return gokey, nil
+	}
+	if gokey, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return gokey, nil
+	}
+	if gokey, err := x509.ParseECPrivateKey(der); err == nil {
+		return gokey, nil
+	}
+	if gokey, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return gokey, nil
+	}
+	if gokey, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return gokey, nil
+	}
+	return nil, makeErrors(ErrInvalidX509, fmt.Errorf("unknown der format"))
+}
+
+// newCertKey make key with certificate chain, options are applied after chain is set
+func newCertKey(gokey interface{}, chain []*x509.Certificate, options ...OptionalNewKey) (Key, error) {
+	key, err := NewKey(gokey)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) > 0 {
+		x5t := sha1.Sum(chain[0].Raw)
+		x5tS256 := sha256.Sum256(chain[0].Raw)
+		bk := key.intoBaseKey()
+		bk.X509CertChain = chain
+		bk.X509CertThumbprint = x5t[:]
+		bk.X509CertThumbprintS256 = x5tS256[:]
+	}
+	return NewKey(key, options...)
+}
+
+func isSamePublicKey(gokey interface{}, pubk crypto.PublicKey) bool {
+	if prik, ok := gokey.(interface{ Public() crypto.PublicKey }); ok {
+		gokey = prik.Public()
+	}
+	if eq, ok := gokey.(interface{ Equal(crypto.PublicKey) bool }); ok {
+		return eq.Equal(pubk)
+	}
+	return false
+}
+
+type (
+	OptionMarshalPEM struct {
+		// PublicOnly write public key even if key is private key, key without public key like `oct` or unknown key is skipped in set
+		PublicOnly bool
+		// PKCS1 write RSA key as PKCS #1, `RSA PRIVATE KEY` or `RSA PUBLIC KEY`
+		PKCS1 bool
+		// SEC1 write EC private key as SEC 1, `EC PRIVATE KEY`
+		SEC1 bool
+		// Certificates write `x5c` as `CERTIFICATE` blocks after key
+		Certificates bool
+	}
+	OptionalMarshalPEM interface {
+		WithMarshalPEM(*OptionMarshalPEM)
+	}
+	WithPublicOnly   bool
+	WithPKCS1        bool
+	WithSEC1         bool
+	WithCertificates bool
+)
+
+func (w WithPublicOnly) WithMarshalPEM(o *OptionMarshalPEM)   { o.PublicOnly = bool(w) }
+func (w WithPKCS1) WithMarshalPEM(o *OptionMarshalPEM)        { o.PKCS1 = bool(w) }
+func (w WithSEC1) WithMarshalPEM(o *OptionMarshalPEM)         { o.SEC1 = bool(w) }
+func (w WithCertificates) WithMarshalPEM(o *OptionMarshalPEM) { o.Certificates = bool(w) }
+
+func (key *UnknownKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *RSAPrivateKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *RSAPublicKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *ECPrivateKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *ECPublicKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *SymetricKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *OKPPrivateKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *OKPPublicKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *ECDHPrivateKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+func (key *ECDHPublicKey) MarshalPEM(options ...OptionalMarshalPEM) ([]byte, error) {
+	return marshalPEM(key, options)
+}
+
+// EncodeSetPEM write every key in set as PEM bundle, `x5c` is written as `CERTIFICATE` blocks by default
+func EncodeSetPEM(src *Set, dst io.Writer, options ...OptionalMarshalPEM) error {
+	if src == nil {
+		return makeErrors(ErrNil, fmt.Errorf("src is not nilable"))
+	}
+	if dst == nil {
+		return makeErrors(ErrNil, fmt.Errorf("dst is not nilable"))
+	}
+	option := &OptionMarshalPEM{Certificates: true}
+	for _, o := range options {
+		o.WithMarshalPEM(option)
+	}
+	for i, k := range src.Keys {
+		if k == nil {
+			return makeErrors(ErrInnerKey, FieldError("keys"), IndexError(i), ErrNil)
+		}
+		if option.PublicOnly && (k.Kty() == KeyTypeOctet || k.IntoPublicKey() == nil) {
+			continue
+		}
+		if err := writePEM(dst, k, option); err != nil {
+			return makeErrors(ErrInnerKey, FieldError("keys"), IndexError(i), err)
+		}
+	}
+	return nil
+}
+
+func marshalPEM(key Key, options []OptionalMarshalPEM) ([]byte, error) {
+	option := &OptionMarshalPEM{}
+	for _, o := range options {
+		o.WithMarshalPEM(option)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := writePEM(buf, key, option); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writePEM(dst io.Writer, key Key, option *OptionMarshalPEM) error {
+	block, err := keyPEMBlock(key, option)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(dst, block); err != nil {
+		return err
+	}
+	if option.Certificates {
+		for _, cert := range key.X5c() {
+			if err := pem.Encode(dst, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func keyPEMBlock(key Key, option *OptionMarshalPEM) (*pem.Block, error) {
+	if key.Kty() == KeyTypeOctet || key.IntoKey() == nil {
+		return nil, makeErrors(ErrUnsupportedKeyType, fmt.Errorf("can't write kty='%s' as pem", key.Kty()))
+	}
+	var (
+		private             = !option.PublicOnly && key.IntoPrivateKey() != nil
+		gokey   interface{} = key.IntoPublicKey()
+		block               = &pem.Block{}
+		err     error
+	)
+	if private {
+		gokey = key.IntoPrivateKey()
+	}
+	switch k := gokey.(type) {
+	case *rsa.PrivateKey:
+		if option.PKCS1 {
+			block.Type, block.Bytes = "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(k)
+			return block, nil
+		}
+	case *rsa.PublicKey:
+		if option.PKCS1 {
+			block.Type, block.Bytes = "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(k)
+			return block, nil
+		}
+	case *ecdsa.PrivateKey:
+		if option.SEC1 {
+			block.Type = "EC PRIVATE KEY"
+			if block.Bytes, err = x509.MarshalECPrivateKey(k); err != nil {
+				return nil, makeErrors(ErrInvalidX509, err)
+			}
+			return block, nil
+		}
+	}
+	if private {
+		block.Type = "PRIVATE KEY"
+		block.Bytes, err = x509.MarshalPKCS8PrivateKey(gokey)
+	} else {
+		block.Type = "PUBLIC KEY"
+		block.Bytes, err = x509.MarshalPKIXPublicKey(gokey)
+	}
+	if err != nil {
+		return nil, makeErrors(ErrInvalidX509, err)
+	}
+	return block, nil
+}
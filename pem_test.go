@@ -0,0 +1,298 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+var (
+	//go:embed embeding/pem-ec-chain.pem
+	pemECChain []byte
+	//go:embed embeding/pem-cert-bundle.pem
+	pemCertBundle []byte
+	//go:embed embeding/pem-rsa-pkcs1.pem
+	pemRSAPKCS1 []byte
+	//go:embed embeding/pem-rsa-pkix-pub.pem
+	pemRSAPKIXPub []byte
+	//go:embed embeding/pem-ed25519-pkcs8.pem
+	pemEd25519PKCS8 []byte
+)
+
+func TestParsePEM(t *testing.T) {
+	t.Run("ec private key with chain", func(t *testing.T) {
+		k, err := jwk.ParsePEM(pemECChain, jwk.WithAlgorithm(jwk.AlgorithmES256))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, ok := k.(*jwk.ECPrivateKey); !ok {
+			t.Fatalf("expected *jwk.ECPrivateKey, but got %T", k)
+		}
+		if k.Alg() != jwk.AlgorithmES256 {
+			t.Fatalf("expected %s, but got %s", jwk.AlgorithmES256, k.Alg())
+		}
+		if len(k.X5c()) != 2 || k.X5c()[0].Subject.CommonName != "jwk test leaf" || k.X5c()[1].Subject.CommonName != "jwk test root" {
+			t.Fatalf("expected leaf, root chain, but got %v", k.X5c())
+		}
+		x5t := sha1.Sum(k.X5c()[0].Raw)
+		x5tS256 := sha256.Sum256(k.X5c()[0].Raw)
+		if !bytes.Equal(k.X5t(), x5t[:]) || !bytes.Equal(k.X5tS256(), x5tS256[:]) {
+			t.Fatalf("expected thumbprint of leaf certificate, but got %x, %x", k.X5t(), k.X5tS256())
+		}
+	})
+	t.Run("rsa pkcs1 private key", func(t *testing.T) {
+		k, err := jwk.ParsePEM(pemRSAPKCS1)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, ok := k.IntoKey().(*rsa.PrivateKey); !ok {
+			t.Fatalf("expected *rsa.PrivateKey, but got %T", k.IntoKey())
+		}
+	})
+	t.Run("rsa pkix public key", func(t *testing.T) {
+		k, err := jwk.ParsePEM(pemRSAPKIXPub)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		rsak, err := jwk.ParsePEM(pemRSAPKCS1)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if !rsak.IntoPublicKey().(*rsa.PublicKey).Equal(k.IntoKey()) {
+			t.Fatalf("expected public key of pkcs1 private key, but not")
+		}
+	})
+	t.Run("ed25519 pkcs8 private key", func(t *testing.T) {
+		k, err := jwk.ParsePEM(pemEd25519PKCS8)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if _, ok := k.IntoKey().(ed25519.PrivateKey); !ok {
+			t.Fatalf("expected ed25519.PrivateKey, but got %T", k.IntoKey())
+		}
+	})
+	t.Run("no pem", func(t *testing.T) {
+		_, err := jwk.ParsePEM([]byte("not a pem"))
+		if !errors.Is(err, jwk.ErrInvalidPEM) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrInvalidPEM, err)
+		}
+	})
+	t.Run("invalid block", func(t *testing.T) {
+		_, err := jwk.ParsePEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{0x30, 0x00}}))
+		if !errors.Is(err, jwk.ErrInvalidPEM) || !errors.Is(err, jwk.ErrInvalidX509) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrInvalidX509, err)
+		}
+	})
+}
+
+func TestParsePEMSet(t *testing.T) {
+	t.Run("certificate bundle", func(t *testing.T) {
+		s, err := jwk.ParsePEMSet(pemCertBundle)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(s.Keys) != 2 {
+			t.Fatalf("expected 2 keys, but got %d", len(s.Keys))
+		}
+		if _, ok := s.Keys[0].IntoKey().(ed25519.PublicKey); !ok || len(s.Keys[0].X5c()) != 1 {
+			t.Fatalf("expected ed25519 key with self-signed certificate, but got %T, %d", s.Keys[0].IntoKey(), len(s.Keys[0].X5c()))
+		}
+		if _, ok := s.Keys[1].IntoKey().(*ecdsa.PublicKey); !ok || len(s.Keys[1].X5c()) != 2 {
+			t.Fatalf("expected ec key with leaf, root chain, but got %T, %d", s.Keys[1].IntoKey(), len(s.Keys[1].X5c()))
+		}
+	})
+	t.Run("multiple keys", func(t *testing.T) {
+		data := bytes.Join([][]byte{pemRSAPKCS1, pemEd25519PKCS8, pemECChain}, nil)
+		s, err := jwk.ParsePEMSet(data, jwk.WithKeyIDFunc(jwk.KeyIDThumbprint))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(s.Keys) != 3 {
+			t.Fatalf("expected 3 keys, but got %d", len(s.Keys))
+		}
+		for i, kty := range []jwk.KeyType{jwk.KeyTypeRSA, jwk.KeyTypeOKP, jwk.KeyTypeEC} {
+			if s.Keys[i].Kty() != kty || len(s.Keys[i].Kid()) == 0 {
+				t.Fatalf("expected %s with kid, but got %s, '%s'", kty, s.Keys[i].Kty(), s.Keys[i].Kid())
+			}
+		}
+		if len(s.Keys[2].X5c()) != 2 {
+			t.Fatalf("expected chain for ec key, but got %d", len(s.Keys[2].X5c()))
+		}
+	})
+}
+
+func TestParseDER(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"pkcs1 private key": pemRSAPKCS1,
+		"pkix public key":   pemRSAPKIXPub,
+		"pkcs8 private key": pemEd25519PKCS8,
+		"sec1 private key":  pemECChain,
+	} {
+		t.Run(name, func(t *testing.T) {
+			block, _ := pem.Decode(data)
+			k, err := jwk.ParseDER(block.Bytes)
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			expected, _ := jwk.ParsePEM(pem.EncodeToMemory(block))
+			tp, _ := k.Thumbprint(crypto.SHA256)
+			expectedTp, _ := expected.Thumbprint(crypto.SHA256)
+			if !bytes.Equal(tp, expectedTp) {
+				t.Fatalf("expected same key with pem, but not")
+			}
+		})
+	}
+	t.Run("certificate", func(t *testing.T) {
+		block, _ := pem.Decode(pemCertBundle)
+		k, err := jwk.ParseDER(block.Bytes)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(k.X5c()) != 1 || len(k.X5t()) != sha1.Size || len(k.X5tS256()) != sha256.Size {
+			t.Fatalf("expected certificate fields, but got %d, %x, %x", len(k.X5c()), k.X5t(), k.X5tS256())
+		}
+	})
+	t.Run("unknown", func(t *testing.T) {
+		_, err := jwk.ParseDER([]byte{0x01, 0x02})
+		if !errors.Is(err, jwk.ErrInvalidX509) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrInvalidX509, err)
+		}
+	})
+}
+
+func TestMarshalPEM(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		src     []byte
+		options []jwk.OptionalMarshalPEM
+		block   string
+	}{
+		{"pkcs8 rsa private key", pemRSAPKCS1, nil, "PRIVATE KEY"},
+		{"pkcs1 rsa private key", pemRSAPKCS1, []jwk.OptionalMarshalPEM{jwk.WithPKCS1(true)}, "RSA PRIVATE KEY"},
+		{"pkcs1 rsa public key", pemRSAPKCS1, []jwk.OptionalMarshalPEM{jwk.WithPKCS1(true), jwk.WithPublicOnly(true)}, "RSA PUBLIC KEY"},
+		{"pkix rsa public key", pemRSAPKIXPub, nil, "PUBLIC KEY"},
+		{"pkcs8 ec private key", pemECChain, nil, "PRIVATE KEY"},
+		{"sec1 ec private key", pemECChain, []jwk.OptionalMarshalPEM{jwk.WithSEC1(true)}, "EC PRIVATE KEY"},
+		{"pkix ec public key", pemECChain, []jwk.OptionalMarshalPEM{jwk.WithPublicOnly(true)}, "PUBLIC KEY"},
+		{"pkcs8 ed25519 private key", pemEd25519PKCS8, nil, "PRIVATE KEY"},
+		{"pkix ed25519 public key", pemEd25519PKCS8, []jwk.OptionalMarshalPEM{jwk.WithPublicOnly(true)}, "PUBLIC KEY"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := jwk.ParsePEM(tc.src)
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			data, err := k.MarshalPEM(tc.options...)
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			block, rest := pem.Decode(data)
+			if block == nil || block.Type != tc.block || len(rest) != 0 {
+				t.Fatalf("expected single '%s' block, but got %s", tc.block, data)
+			}
+			rk, err := jwk.ParsePEM(data)
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			if private := strings.HasSuffix(block.Type, "PRIVATE KEY"); (rk.IntoPrivateKey() != nil) != private {
+				t.Fatalf("expected private key %v, but not", private)
+			}
+			tp, _ := k.Thumbprint(crypto.SHA256)
+			rtp, _ := rk.Thumbprint(crypto.SHA256)
+			if !bytes.Equal(tp, rtp) {
+				t.Fatalf("expected same key, but not")
+			}
+		})
+	}
+	t.Run("certificates", func(t *testing.T) {
+		k, _ := jwk.ParsePEM(pemECChain)
+		data, err := k.MarshalPEM(jwk.WithCertificates(true))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		rk, err := jwk.ParsePEM(data)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(rk.X5c()) != 2 || !bytes.Equal(rk.X5t(), k.X5t()) {
+			t.Fatalf("expected same certificate chain, but not")
+		}
+	})
+	t.Run("symetric key", func(t *testing.T) {
+		_, err := jwk.MustKey([]byte("secret")).MarshalPEM()
+		if !errors.Is(err, jwk.ErrUnsupportedKeyType) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrUnsupportedKeyType, err)
+		}
+	})
+}
+
+func TestEncodeSetPEM(t *testing.T) {
+	s, err := jwk.ParsePEMSet(bytes.Join([][]byte{pemRSAPKCS1, pemECChain}, nil))
+	if err != nil {
+		t.Fatalf("expected <nil>, but got %v", err)
+	}
+	t.Run("bundle", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeSetPEM(s, buf); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		var types []string
+		for rest := buf.Bytes(); ; {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+			types = append(types, block.Type)
+		}
+		expected := []string{"PRIVATE KEY", "PRIVATE KEY", "CERTIFICATE", "CERTIFICATE"}
+		if strings.Join(types, ",") != strings.Join(expected, ",") {
+			t.Fatalf("expected %v, but got %v", expected, types)
+		}
+		rs, err := jwk.ParsePEMSet(buf.Bytes())
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(rs.Keys) != 2 || len(rs.Keys[1].X5c()) != 2 {
+			t.Fatalf("expected same set, but not")
+		}
+	})
+	t.Run("public only", func(t *testing.T) {
+		ed448 := jwk.MustDecodeKey(strings.NewReader(`{"kty":"OKP","crv":"Ed448","x":"X9dEm1m0Yf0s54fsYWrUah2hNCSFpw4fig6nXYDpZ3jt8SR2m0bHBhvWeD3x5Q9s0foavq_oJWGA"}`))
+		if _, ok := ed448.(*jwk.UnknownKey); !ok {
+			t.Fatalf("expected %T, but got %T", new(jwk.UnknownKey), ed448)
+		}
+		ps := jwk.NewSet(append([]jwk.Key{jwk.MustKey([]byte("secret")), ed448}, s.Keys...)...)
+		buf := bytes.NewBuffer(nil)
+		if err := jwk.EncodeSetPEM(ps, buf, jwk.WithPublicOnly(true), jwk.WithCertificates(false)); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if strings.Count(buf.String(), "-----BEGIN PUBLIC KEY-----") != 2 || strings.Count(buf.String(), "-----BEGIN") != 2 {
+			t.Fatalf("expected 2 public keys only, but got %s", buf.String())
+		}
+	})
+	t.Run("symetric key", func(t *testing.T) {
+		err := jwk.EncodeSetPEM(jwk.NewSet(jwk.MustKey([]byte("secret"))), bytes.NewBuffer(nil))
+		if !errors.Is(err, jwk.ErrInnerKey) || !errors.Is(err, jwk.ErrUnsupportedKeyType) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrUnsupportedKeyType, err)
+		}
+	})
+	t.Run("nil", func(t *testing.T) {
+		if err := jwk.EncodeSetPEM(nil, bytes.NewBuffer(nil)); !errors.Is(err, jwk.ErrNil) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrNil, err)
+		}
+		if err := jwk.EncodeSetPEM(s, nil); !errors.Is(err, jwk.ErrNil) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrNil, err)
+		}
+	})
+}
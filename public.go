@@ -0,0 +1,119 @@
+package jwk
+
+import "crypto/ed25519"
+
+// private members of every key type, they must not be in public key
+// https://www.rfc-editor.org/rfc/rfc7518#section-6
+var privateMembers = []string{"d", "p", "q", "dp", "dq", "qi", "oth", "k"}
+
+// Unknown key without private members is public key, so it return its clone, otherwise it return nil
+func (key *UnknownKey) Public() Key {
+	for _, member := range privateMembers {
+		if _, ok := key.extra[member]; ok {
+			return nil
+		}
+	}
+	return key.Clone()
+}
+
+func (key *RSAPrivateKey) Public() Key {
+	return &RSAPublicKey{
+		BaseKey: key.BaseKey.public(),
+		Key:     cloneRSAPublicKey(&key.Key.PublicKey),
+	}
+}
+
+func (key *RSAPublicKey) Public() Key {
+	return key.Clone()
+}
+
+func (key *ECPrivateKey) Public() Key {
+	return &ECPublicKey{
+		BaseKey: key.BaseKey.public(),
+		Key:     cloneECPublicKey(&key.Key.PublicKey),
+	}
+}
+
+func (key *ECPublicKey) Public() Key {
+	return key.Clone()
+}
+
+func (key *SymetricKey) Public() Key {
+	return nil
+}
+
+func (key *OKPPrivateKey) Public() Key {
+	return &OKPPublicKey{
+		BaseKey: key.BaseKey.public(),
+		Key:     key.Key.Public().(ed25519.PublicKey),
+	}
+}
+
+func (key *OKPPublicKey) Public() Key {
+	return key.Clone()
+}
+
+func (key *ECDHPrivateKey) Public() Key {
+	return &ECDHPublicKey{
+		BaseKey: key.BaseKey.public(),
+		Key:     key.Key.PublicKey(),
+	}
+}
+
+func (key *ECDHPublicKey) Public() Key {
+	return key.Clone()
+}
+
+// Public return new set with public keys of every key in set, key without public key like `oct` is dropped
+// Unknown key is kept only when it has none of private members like `d`, `p` or `k`
+// Keys of result are not shared with set
+func (set *Set) Public() *Set {
+	res := &Set{
+		Keys:  make([]Key, 0, len(set.Keys)),
+		Extra: make(map[string]interface{}, len(set.Extra)),
+	}
+	for _, k := range set.Keys {
+		if k == nil {
+			continue
+		}
+		if pubk := k.Public(); pubk != nil {
+			res.Keys = append(res.Keys, pubk)
+		}
+	}
+	for k, v := range set.Extra {
+		res.Extra[k] = v
+	}
+	return res
+}
+
+// public copy base key for public key, operations only for private key are replaced with pair of that
+//   - `sign` -> `verify`
+//   - `decrypt` -> `encrypt`
+//   - `unwrapKey` -> `wrapKey`
+func (bk *BaseKey) public() BaseKey {
+	res := bk.clone()
+	res.KeyOperations = make(KeyOps, len(bk.KeyOperations))
+	for op := range bk.KeyOperations {
+		res.KeyOperations[publicKeyOp(op)] = struct{}{}
+	}
+	for i, op := range res.keyOpsOrder {
+		res.keyOpsOrder[i] = publicKeyOp(op)
+	}
+	for _, member := range privateMembers {
+		delete(res.extra, member)
+	}
+	return res
+}
+
+// publicKeyOp return op of public key for op of private key
+func publicKeyOp(op KeyOp) KeyOp {
+	switch op {
+	case KeyOpSign:
+		return KeyOpVerify
+	case KeyOpDecrypt:
+		return KeyOpEncrypt
+	case KeyOpUnwrapKey:
+		return KeyOpWrapKey
+	}
+	return op
+}
@@ -0,0 +1,111 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto"
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestPublic(t *testing.T) {
+	t.Run("private keys", func(t *testing.T) {
+		for _, src := range []string{encRSAPri, encECPri, encOKPPri, encX25519Pri, encRSAPriMultiPrime} {
+			k, err := jwk.DecodeKey(strings.NewReader(src))
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			pubk := k.Public()
+			if pubk == nil {
+				t.Fatalf("expected public key of %T, but got <nil>", k)
+			}
+			if pubk.IntoPrivateKey() != nil {
+				t.Fatalf("expected public key, but got %T", pubk)
+			}
+			if pubk.Kid() != k.Kid() || pubk.Alg() != k.Alg() || pubk.Use() != k.Use() {
+				t.Fatalf("expected same base key, but not")
+			}
+			tp, _ := k.Thumbprint(crypto.SHA256)
+			pubtp, _ := pubk.Thumbprint(crypto.SHA256)
+			if !bytes.Equal(tp, pubtp) {
+				t.Fatalf("expected same key material, but not")
+			}
+			// private members must not leak even after key encoded
+			k.MarshalJSON()
+			bts, err := pubk.MarshalJSON()
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			for _, member := range []string{`"d"`, `"p"`, `"q"`, `"dp"`, `"dq"`, `"qi"`, `"oth"`} {
+				if bytes.Contains(bts, []byte(member)) {
+					t.Fatalf("expected no %s, but got %s", member, bts)
+				}
+			}
+			if again := pubk.Public(); again == pubk || !again.Equal(pubk, jwk.EqualFull) {
+				t.Fatalf("expected public key of public key is its clone, but not")
+			}
+		}
+	})
+	t.Run("types", func(t *testing.T) {
+		if _, ok := jwk.MustDecodeKey(strings.NewReader(encRSAPri)).Public().(*jwk.RSAPublicKey); !ok {
+			t.Fatalf("expected *jwk.RSAPublicKey, but not")
+		}
+		if _, ok := jwk.MustDecodeKey(strings.NewReader(encECPri)).Public().(*jwk.ECPublicKey); !ok {
+			t.Fatalf("expected *jwk.ECPublicKey, but not")
+		}
+		if _, ok := jwk.MustDecodeKey(strings.NewReader(encOKPPri)).Public().(*jwk.OKPPublicKey); !ok {
+			t.Fatalf("expected *jwk.OKPPublicKey, but not")
+		}
+		if _, ok := jwk.MustDecodeKey(strings.NewReader(encX25519Pri)).Public().(*jwk.ECDHPublicKey); !ok {
+			t.Fatalf("expected *jwk.ECDHPublicKey, but not")
+		}
+	})
+	t.Run("key_ops", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(encECPri))
+		k.(*jwk.ECPrivateKey).KeyOperations = jwk.KeyOps{jwk.KeyOpSign: struct{}{}}
+		pubk := k.Public()
+		if !pubk.KeyOps().In(jwk.KeyOpVerify) || pubk.KeyOps().In(jwk.KeyOpSign) {
+			t.Fatalf("expected [verify], but got %v", pubk.KeyOps().AsSlice())
+		}
+		if !k.KeyOps().In(jwk.KeyOpSign) {
+			t.Fatalf("expected private key unchanged, but got %v", k.KeyOps().AsSlice())
+		}
+	})
+	t.Run("symetric key", func(t *testing.T) {
+		if pubk := jwk.MustKey([]byte("secret")).Public(); pubk != nil {
+			t.Fatalf("expected <nil>, but got %v", pubk)
+		}
+	})
+}
+
+func TestSetPublic(t *testing.T) {
+	s := jwk.NewSet(
+		jwk.MustDecodeKey(strings.NewReader(encRSAPri)),
+		jwk.MustKey([]byte("secret")),
+		jwk.MustDecodeKey(strings.NewReader(encECPub)),
+		jwk.MustDecodeKey(strings.NewReader(`{"kty":"OKP","crv":"Ed448","kid":"public","x":"X9dEm1m0Yf0s54fsYWrUah2hNCSFpw4fig6nXYDpZ3jt8SR2m0bHBhvWeD3x5Q9s0foavq_oJWGA"}`)),
+		jwk.MustDecodeKey(strings.NewReader(`{"kty":"OKP","crv":"Ed448","kid":"private","x":"X9dEm1m0Yf0s54fsYWrUah2hNCSFpw4fig6nXYDpZ3jt8SR2m0bHBhvWeD3x5Q9s0foavq_oJWGA","d":"bOKRl9TrvL6VRcLJnFdhB7eF5TEIH6DW_t-D43-2bvpxP9M9Zq5xYpVuCcM3MkK3FwPAVPVDkAP-"}`)),
+	)
+	s.Extra["name"] = "set"
+	ps := s.Public()
+	if len(ps.Keys) != 3 {
+		t.Fatalf("expected 3 keys, but got %d", len(ps.Keys))
+	}
+	if _, ok := ps.Keys[2].(*jwk.UnknownKey); !ok || ps.Keys[2].Kid() != "public" {
+		t.Fatalf("expected public unknown key, but got %T %v", ps.Keys[2], ps.Keys[2].Kid())
+	}
+	for _, k := range ps.Keys {
+		if k.IntoPrivateKey() != nil {
+			t.Fatalf("expected public key, but got %T", k)
+		}
+	}
+	if ps.Extra["name"] != "set" || len(s.Keys) != 5 {
+		t.Fatalf("expected extra copied and origin unchanged, but not")
+	}
+	ps.Keys[1].(*jwk.ECPublicKey).KeyID = "changed"
+	ps.Keys[1].(*jwk.ECPublicKey).Key.X.SetInt64(0)
+	if s.Keys[2].Kid() == "changed" || s.Keys[2].(*jwk.ECPublicKey).Key.X.Sign() == 0 {
+		t.Fatalf("expected origin unchanged, but not")
+	}
+}
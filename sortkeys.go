@@ -14,8 +14,9 @@ type keySortingHelper []Key
 
 var (
 	orderTable = map[KeyType]int{
-		KeyTypeEC:    -3,
-		KeyTypeRSA:   -2,
+		KeyTypeEC:    -4,
+		KeyTypeRSA:   -3,
+		KeyTypeOKP:   -2,
 		KeyTypeOctet: -1,
 	}
 )
@@ -29,9 +30,7 @@ func (ks *keySortingHelper) Less(i int, j int) bool {
 	kj := (*ks)[j]
 	a := strings.Compare(ki.Kid(), kj.Kid())
 	if a == 0 {
-		ii := orderTable[ki.Kty()]
-		ij := orderTable[kj.Kty()]
-		return ii < ij
+		return keyTypeOrder(ki.Kty()) < keyTypeOrder(kj.Kty())
 	}
 	return a < 0
 }
@@ -42,3 +41,18 @@ func (ks *keySortingHelper) Swap(i int, j int) {
 	(*ks)[i] = kj
 	(*ks)[j] = ki
 }
+
+// keyTypeOrder return order of key type, registered key type is after builtin key type and before unknown key type
+func keyTypeOrder(kty KeyType) int {
+	if order, ok := orderTable[kty]; ok {
+		return order
+	}
+	keyTypeMutex.RLock()
+	defer keyTypeMutex.RUnlock()
+	for i, e := range keyTypeTable {
+		if e.kty == kty {
+			return i
+		}
+	}
+	return len(keyTypeTable)
+}
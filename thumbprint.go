@@ -0,0 +1,196 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc7638
+func (key *UnknownKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return nil, makeErrors(ErrUnsupportedKeyType, fmt.Errorf("can't decide required members of kty='%s'", key.KeyType))
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc7638
+func (key *RSAPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubRSA(data, &key.Key.PublicKey)
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc7638
+func (key *RSAPublicKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubRSA(data, key.Key)
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc7638
+func (key *ECPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubEC(data, &key.Key.PublicKey)
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc7638
+func (key *ECPublicKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubEC(data, key.Key)
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc7638
+func (key *SymetricKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodeSym(data, key.Key)
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc8037#appendix-A.3
+func (key *OKPPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubOKP(data, key.Key.Public().(ed25519.PublicKey))
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc8037#appendix-A.3
+func (key *OKPPublicKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubOKP(data, key.Key)
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc8037#appendix-A.3
+func (key *ECDHPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubECDH(data, key.Key.PublicKey())
+	})
+}
+
+// JWK Thumbprint
+// https://www.rfc-editor.org/rfc/rfc8037#appendix-A.3
+func (key *ECDHPublicKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
+	return thumbprint(hash, key.Kty(), func(data map[string]interface{}) {
+		encodePubECDH(data, key.Key)
+	})
+}
+
+// GetKeyByThumbprint return first key which has same JWK Thumbprint
+// Keys that can't make thumbprint, like `UnknownKey`, are skipped
+func (set *Set) GetKeyByThumbprint(hash crypto.Hash, thumbprint []byte) Key {
+	for _, k := range set.Keys {
+		if tp, err := k.Thumbprint(hash); err == nil && bytes.Equal(tp, thumbprint) {
+			return k
+		}
+	}
+	return nil
+}
+
+// thumbprint hash JSON object of required members
+// `encoding/json` sort keys of map and it doesn't make any whitespace, so it is canonical form for required members
+// https://www.rfc-editor.org/rfc/rfc7638#section-3.3
+func thumbprint(hash crypto.Hash, kty KeyType, required func(map[string]interface{})) ([]byte, error) {
+	if !hash.Available() {
+		return nil, makeErrors(ErrParameter, ErrUnavailableHash, fmt.Errorf("hash %v", hash))
+	}
+	data := map[string]interface{}{"kty": kty}
+	required(data)
+	buf := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(data); err != nil {
+		return nil, makeErrors(ErrInvalidJSON, err)
+	}
+	h := hash.New()
+	h.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return h.Sum(nil), nil
+}
+
+// https://www.rfc-editor.org/rfc/rfc9278#section-3
+const ThumbprintURIPrefix = "urn:ietf:params:oauth:jwk-thumbprint:"
+
+// Hash Name String from "Named Information Hash Algorithm Registry"
+// https://www.iana.org/assignments/named-information/named-information.xhtml
+var thumbprintURIHashTable = map[string]crypto.Hash{
+	"sha-256":  crypto.SHA256,
+	"sha-384":  crypto.SHA384,
+	"sha-512":  crypto.SHA512,
+	"sha3-224": crypto.SHA3_224,
+	"sha3-256": crypto.SHA3_256,
+	"sha3-384": crypto.SHA3_384,
+	"sha3-512": crypto.SHA3_512,
+}
+
+// ThumbprintURI return JWK Thumbprint URI of key
+// for example, `urn:ietf:params:oauth:jwk-thumbprint:sha-256:NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs`
+// https://www.rfc-editor.org/rfc/rfc9278
+func ThumbprintURI(key Key, hash crypto.Hash) (string, error) {
+	if key == nil {
+		return "", makeErrors(ErrNil, fmt.Errorf("key is not nilable"))
+	}
+	var name string
+	for n, h := range thumbprintURIHashTable {
+		if h == hash {
+			name = n
+			break
+		}
+	}
+	if len(name) == 0 {
+		return "", makeErrors(ErrParameter, ErrUnavailableHash, fmt.Errorf("hash %v has no registered name", hash))
+	}
+	tp, err := key.Thumbprint(hash)
+	if err != nil {
+		return "", err
+	}
+	return ThumbprintURIPrefix + name + ":" + base64.RawURLEncoding.EncodeToString(tp), nil
+}
+
+// ParseThumbprintURI return hash algorithm and thumbprint of JWK Thumbprint URI
+// https://www.rfc-editor.org/rfc/rfc9278
+func ParseThumbprintURI(uri string) (crypto.Hash, []byte, error) {
+	if len(uri) < len(ThumbprintURIPrefix) || !strings.EqualFold(uri[:len(ThumbprintURIPrefix)], ThumbprintURIPrefix) {
+		return 0, nil, makeErrors(ErrInvalidThumbprintURI, fmt.Errorf("expected prefix '%s'", ThumbprintURIPrefix))
+	}
+	rest := strings.SplitN(uri[len(ThumbprintURIPrefix):], ":", 2)
+	if len(rest) != 2 {
+		return 0, nil, makeErrors(ErrInvalidThumbprintURI, fmt.Errorf("expected '<hash-algorithm>:<thumbprint>'"))
+	}
+	hash, ok := thumbprintURIHashTable[rest[0]]
+	if !ok {
+		return 0, nil, makeErrors(ErrInvalidThumbprintURI, ErrUnavailableHash, fmt.Errorf("unknown hash algorithm '%s'", rest[0]))
+	}
+	tp, err := base64.RawURLEncoding.DecodeString(rest[1])
+	if err != nil {
+		return 0, nil, makeErrors(ErrInvalidThumbprintURI, ErrInvalidBase64, err)
+	}
+	if len(tp) != hash.Size() {
+		return 0, nil, makeErrors(ErrInvalidThumbprintURI, fmt.Errorf("expected length %d, but got %d", hash.Size(), len(tp)))
+	}
+	return hash, tp, nil
+}
+
+// GetKeyByThumbprintURI return first key matched with JWK Thumbprint URI
+// It return <nil> when uri is invalid
+func (set *Set) GetKeyByThumbprintURI(uri string) Key {
+	hash, tp, err := ParseThumbprintURI(uri)
+	if err != nil {
+		return nil
+	}
+	return set.GetKeyByThumbprint(hash, tp)
+}
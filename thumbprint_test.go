@@ -0,0 +1,143 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestThumbprint(t *testing.T) {
+	t.Run("rsa", func(t *testing.T) {
+		// https://www.rfc-editor.org/rfc/rfc7638#section-3.1
+		k, err := jwk.DecodeKey(strings.NewReader(`{
+			"kty": "RSA",
+			"n": "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+			"e": "AQAB",
+			"alg": "RS256",
+			"kid": "2011-04-29"
+		}`))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		tp, err := k.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if s := base64.RawURLEncoding.EncodeToString(tp); s != "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs" {
+			t.Fatalf("expected %v, but got %v", "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", s)
+		}
+	})
+	t.Run("okp", func(t *testing.T) {
+		// https://www.rfc-editor.org/rfc/rfc8037#appendix-A.3
+		for _, src := range []string{okpPriValid, okpPubValid} {
+			k, err := jwk.DecodeKey(strings.NewReader(src))
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			tp, err := k.Thumbprint(crypto.SHA256)
+			if err != nil {
+				t.Fatalf("expected <nil>, but got %v", err)
+			}
+			if s := base64.RawURLEncoding.EncodeToString(tp); s != "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k" {
+				t.Fatalf("expected %v, but got %v", "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", s)
+			}
+		}
+	})
+	t.Run("private and public", func(t *testing.T) {
+		prik := jwk.MustDecodeKey(strings.NewReader(ecPriValid))
+		pubk := jwk.MustDecodeKey(strings.NewReader(ecPubValid))
+		tpa, err := prik.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		tpb, err := pubk.Thumbprint(crypto.SHA256)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if !bytes.Equal(tpa, tpb) {
+			t.Fatalf("expected %x equal %x, but not", tpa, tpb)
+		}
+	})
+	t.Run("unknown", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(unknownUnknownKty))
+		if _, err := k.Thumbprint(crypto.SHA256); !errors.Is(err, jwk.ErrUnsupportedKeyType) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrUnsupportedKeyType)
+		}
+	})
+	t.Run("unavailable hash", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(octetValid))
+		if _, err := k.Thumbprint(crypto.MD4); !errors.Is(err, jwk.ErrUnavailableHash) {
+			t.Fatalf("expected %v is %v, but not", err, jwk.ErrUnavailableHash)
+		}
+	})
+	t.Run("set", func(t *testing.T) {
+		set := jwk.NewSet(
+			jwk.MustDecodeKey(strings.NewReader(unknownUnknownKty)),
+			jwk.MustDecodeKey(strings.NewReader(octetValid)),
+			jwk.MustDecodeKey(strings.NewReader(okpPubValid)),
+		)
+		tp, _ := base64.RawURLEncoding.DecodeString("kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k")
+		if k := set.GetKeyByThumbprint(crypto.SHA256, tp); k != set.Keys[2] {
+			t.Fatalf("expected %v, but got %v", set.Keys[2], k)
+		}
+		if k := set.GetKeyByThumbprint(crypto.SHA384, tp); k != nil {
+			t.Fatalf("expected <nil>, but got %v", k)
+		}
+	})
+}
+
+func TestThumbprintURI(t *testing.T) {
+	const uri = "urn:ietf:params:oauth:jwk-thumbprint:sha-256:kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+	t.Run("render", func(t *testing.T) {
+		k := jwk.MustDecodeKey(strings.NewReader(okpPubValid))
+		s, err := jwk.ThumbprintURI(k, crypto.SHA256)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if s != uri {
+			t.Fatalf("expected %v, but got %v", uri, s)
+		}
+	})
+	t.Run("parse", func(t *testing.T) {
+		hash, tp, err := jwk.ParseThumbprintURI(uri)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if hash != crypto.SHA256 {
+			t.Fatalf("expected %v, but got %v", crypto.SHA256, hash)
+		}
+		if s := base64.RawURLEncoding.EncodeToString(tp); s != "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k" {
+			t.Fatalf("expected %v, but got %v", "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", s)
+		}
+	})
+	t.Run("parse invalid", func(t *testing.T) {
+		for _, invalid := range []string{
+			"urn:ietf:params:oauth:jwk-thumbprint:sha-256",
+			"urn:ietf:params:oauth:jwk-thumbprint:md5:kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+			"urn:ietf:params:oauth:jwk-thumbprint:sha-256:kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4",
+			"urn:ietf:params:oauth:jwk-thumbprint:sha-384:kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k",
+			"https://example.com/",
+		} {
+			if _, _, err := jwk.ParseThumbprintURI(invalid); !errors.Is(err, jwk.ErrInvalidThumbprintURI) {
+				t.Fatalf("%s : expected %v is %v, but not", invalid, err, jwk.ErrInvalidThumbprintURI)
+			}
+		}
+	})
+	t.Run("set", func(t *testing.T) {
+		set := jwk.NewSet(
+			jwk.MustDecodeKey(strings.NewReader(octetValid)),
+			jwk.MustDecodeKey(strings.NewReader(okpPriValid)),
+		)
+		if k := set.GetKeyByThumbprintURI(uri); k != set.Keys[1] {
+			t.Fatalf("expected %v, but got %v", set.Keys[1], k)
+		}
+		if k := set.GetKeyByThumbprintURI("invalid"); k != nil {
+			t.Fatalf("expected <nil>, but got %v", k)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package jwk
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OptionVerifyX5c struct {
+	// If Roots is not nil, chain must be verified to one of roots
+	Roots *x509.CertPool
+	// If CurrentTime is not zero, every certificate must be valid at that time
+	CurrentTime time.Time
+	// If KeyUsages is not empty, leaf certificate must allow one of usages
+	KeyUsages []x509.ExtKeyUsage
+}
+
+// VerifyX5c check `x5c` of key
+//   - leaf certificate has same public key with key
+//   - each certificate is signed by next certificate
+//   - optional, chain is verified to `Roots`, with `CurrentTime`, `KeyUsages`
+//
+// option can be nil
+func VerifyX5c(key Key, option *OptionVerifyX5c) error {
+	if key == nil {
+		return makeErrors(ErrNil, fmt.Errorf("key is not nilable"))
+	}
+	if option == nil {
+		option = &OptionVerifyX5c{}
+	}
+	chain := key.X5c()
+	if len(chain) == 0 {
+		return makeErrors(FieldError("x5c"), ErrNotExist)
+	}
+	if err := verifyCertChain(key, chain); err != nil {
+		return makeErrors(FieldError("x5c"), err)
+	}
+	if !option.CurrentTime.IsZero() {
+		for i, cert := range chain {
+			if option.CurrentTime.Before(cert.NotBefore) || option.CurrentTime.After(cert.NotAfter) {
+				return makeErrors(FieldError("x5c"), IndexError(i), ErrX5cExpired, fmt.Errorf("valid from %v to %v", cert.NotBefore, cert.NotAfter))
+			}
+		}
+	}
+	if option.Roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := chain[0].Verify(x509.VerifyOptions{
+			Roots:         option.Roots,
+			Intermediates: intermediates,
+			CurrentTime:   option.CurrentTime,
+			KeyUsages:     option.KeyUsages,
+		})
+		if err != nil {
+			var invalid x509.CertificateInvalidError
+			if errors.As(err, &invalid) && invalid.Reason == x509.IncompatibleUsage {
+				return makeErrors(FieldError("x5c"), ErrX5cKeyUsage, err)
+			}
+			return makeErrors(FieldError("x5c"), ErrX5cUntrusted, err)
+		}
+	} else if len(option.KeyUsages) > 0 && !allowExtKeyUsage(chain[0], option.KeyUsages) {
+		return makeErrors(FieldError("x5c"), IndexError(0), ErrX5cKeyUsage, fmt.Errorf("certificate allow %v", chain[0].ExtKeyUsage))
+	}
+	return nil
+}
+
+// verifyCertChain check leaf certificate has public key of key, and each certificate is signed by next certificate
+func verifyCertChain(key Key, chain []*x509.Certificate) error {
+	if pubk := key.IntoPublicKey(); key.Kty() == KeyTypeOctet || pubk == nil || !isSamePublicKey(pubk, chain[0].PublicKey) {
+		return makeErrors(IndexError(0), ErrX5cKeyMismatch)
+	}
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return makeErrors(IndexError(i), ErrX5cBrokenChain, err)
+		}
+	}
+	return nil
+}
+
+// allowExtKeyUsage follow `x509.Certificate.Verify`, certificate without extended key usage allow every usage
+func allowExtKeyUsage(cert *x509.Certificate, usages []x509.ExtKeyUsage) bool {
+	if len(cert.ExtKeyUsage) == 0 && len(cert.UnknownExtKeyUsage) == 0 {
+		return true
+	}
+	for _, eku := range cert.ExtKeyUsage {
+		if eku == x509.ExtKeyUsageAny {
+			return true
+		}
+		for _, usage := range usages {
+			if usage == x509.ExtKeyUsageAny || usage == eku {
+				return true
+			}
+		}
+	}
+	return false
+}
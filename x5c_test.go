@@ -0,0 +1,119 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestVerifyX5c(t *testing.T) {
+	chain, _ := jwk.ParsePEMSet(pemCertBundle)
+	edk, eck := chain.Keys[0], chain.Keys[1]
+	roots := x509.NewCertPool()
+	roots.AddCert(eck.X5c()[1])
+
+	t.Run("valid", func(t *testing.T) {
+		if err := jwk.VerifyX5c(eck, nil); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := jwk.VerifyX5c(edk, nil); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("valid roots", func(t *testing.T) {
+		if err := jwk.VerifyX5c(eck, &jwk.OptionVerifyX5c{Roots: roots, CurrentTime: time.Now()}); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("key mismatch", func(t *testing.T) {
+		k, _ := jwk.ParsePEM(pemRSAPKCS1)
+		k.(*jwk.RSAPrivateKey).X509CertChain = eck.X5c()
+		if err := jwk.VerifyX5c(k, nil); !errors.Is(err, jwk.ErrX5cKeyMismatch) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cKeyMismatch, err)
+		}
+	})
+	t.Run("broken chain", func(t *testing.T) {
+		k, _ := jwk.ParsePEM(pemECChain)
+		k.(*jwk.ECPrivateKey).X509CertChain = append(k.X5c()[:1:1], edk.X5c()[0])
+		err := jwk.VerifyX5c(k, nil)
+		if !errors.Is(err, jwk.ErrX5cBrokenChain) || !errors.Is(err, jwk.IndexError(0)) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cBrokenChain, err)
+		}
+	})
+	t.Run("untrusted", func(t *testing.T) {
+		other := x509.NewCertPool()
+		other.AddCert(edk.X5c()[0])
+		if err := jwk.VerifyX5c(eck, &jwk.OptionVerifyX5c{Roots: other}); !errors.Is(err, jwk.ErrX5cUntrusted) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cUntrusted, err)
+		}
+	})
+	t.Run("expired", func(t *testing.T) {
+		past := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		if err := jwk.VerifyX5c(eck, &jwk.OptionVerifyX5c{CurrentTime: past}); !errors.Is(err, jwk.ErrX5cExpired) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cExpired, err)
+		}
+		if err := jwk.VerifyX5c(eck, &jwk.OptionVerifyX5c{Roots: roots, CurrentTime: past}); !errors.Is(err, jwk.ErrX5cExpired) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cExpired, err)
+		}
+	})
+	t.Run("key usage", func(t *testing.T) {
+		prik, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "server"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		der, _ := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &prik.PublicKey, prik)
+		k, err := jwk.ParseDER(der)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := jwk.VerifyX5c(k, &jwk.OptionVerifyX5c{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if err := jwk.VerifyX5c(k, &jwk.OptionVerifyX5c{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); !errors.Is(err, jwk.ErrX5cKeyUsage) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cKeyUsage, err)
+		}
+		self := x509.NewCertPool()
+		self.AddCert(k.X5c()[0])
+		if err := jwk.VerifyX5c(k, &jwk.OptionVerifyX5c{Roots: self, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); !errors.Is(err, jwk.ErrX5cKeyUsage) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cKeyUsage, err)
+		}
+	})
+	t.Run("no x5c", func(t *testing.T) {
+		k, _ := jwk.ParsePEM(pemRSAPKCS1)
+		if err := jwk.VerifyX5c(k, nil); !errors.Is(err, jwk.ErrNotExist) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrNotExist, err)
+		}
+	})
+	t.Run("decode option", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		jwk.MustEncodeKey(eck, buf)
+		option := jwk.WithOptionDecodeKey(func(value *jwk.OptionDecodeKey) { value.VerifyX5c = &jwk.OptionVerifyX5c{Roots: roots} })
+		if _, err := jwk.DecodeKey(bytes.NewReader(buf.Bytes()), option); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		k, _ := jwk.ParsePEM(pemRSAPKCS1)
+		k.(*jwk.RSAPrivateKey).X509CertChain = eck.X5c()
+		buf.Reset()
+		jwk.MustEncodeKey(k, buf)
+		if _, err := jwk.DecodeKey(bytes.NewReader(buf.Bytes())); err != nil {
+			t.Fatalf("expected <nil> without option, but got %v", err)
+		}
+		_, err := jwk.DecodeKey(bytes.NewReader(buf.Bytes()), option)
+		if !errors.Is(err, jwk.ErrRequirement) || !errors.Is(err, jwk.ErrX5cKeyMismatch) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cKeyMismatch, err)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package jwk
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxX5uSize is maximum bytes of `x5u` response when `OptionFetch.MaxX5uSize` is 0
+const DefaultMaxX5uSize int64 = 1 << 20
+
+// ResolveX5u fetch PEM certificate chain from `x5u` of key
+// https://datatracker.ietf.org/doc/html/rfc7517#section-4.6
+func ResolveX5u(key Key, options ...OptionalFetchKey) ([]*x509.Certificate, error) {
+	ctx := context.Background()
+	for _, option := range options {
+		ctx = option.WithFetchKey(ctx)
+	}
+	return ResolveX5uBy(ctx, key)
+}
+
+// ResolveX5uBy fetch PEM certificate chain from `x5u` of key with `OptionFetch.Client`
+// `x5u` must be https url unless `OptionFetch.AllowInsecureX5u`
+// Chain must have public key of key for leaf certificate, and each certificate is signed by next certificate
+// If key has `x5t` or `x5t#S256`, it must be thumbprint of leaf certificate
+// If `OptionFetch.FillX5c` is true, chain is set as `x5c` of key
+// Response larger than `OptionFetch.MaxX5uSize` is rejected with `ErrX5uTooLarge`
+func ResolveX5uBy(ctx context.Context, key Key) ([]*x509.Certificate, error) {
+	if key == nil {
+		return nil, makeErrors(ErrNil, fmt.Errorf("key is not nilable"))
+	}
+	var option *OptionFetch
+	MustGetOptionFromContext(ctx, &option, false)
+	//
+	x5u := key.X5u()
+	if x5u == nil {
+		return nil, makeErrors(FieldError("x5u"), ErrNotExist)
+	}
+	if x5u.Scheme != "https" && !(option.AllowInsecureX5u && x5u.Scheme == "http") {
+		return nil, makeErrors(FieldError("x5u"), ErrInvalidURL, fmt.Errorf("unexpected scheme '%s'", x5u.Scheme))
+	}
+	client := option.Client
+	if !option.AllowInsecureX5u {
+		client = httpsOnlyClient(client)
+	}
+	res, err := utilResponse(x5u, ctx, client)
+	if err != nil {
+		return nil, makeErrors(FieldError("x5u"), err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, makeErrors(FieldError("x5u"), ErrHTTPRequest, fmt.Errorf("unexpected status code %d", res.StatusCode))
+	}
+	limit := option.MaxX5uSize
+	if limit <= 0 {
+		limit = DefaultMaxX5uSize
+	}
+	// read one more byte to know whether response exceed limit
+	data, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	if err != nil {
+		return nil, makeErrors(FieldError("x5u"), ErrHTTPRequest, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, makeErrors(FieldError("x5u"), ErrX5uTooLarge, fmt.Errorf("response exceed %d bytes", limit))
+	}
+	var chain []*x509.Certificate
+	for i := 0; ; i++ {
+		var block *pem.Block
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, makeErrors(FieldError("x5u"), IndexError(i), ErrInvalidX509, err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, makeErrors(FieldError("x5u"), ErrInvalidPEM, fmt.Errorf("no certificate"))
+	}
+	if err := verifyCertChain(key, chain); err != nil {
+		return nil, makeErrors(FieldError("x5u"), err)
+	}
+	if x5t := key.X5t(); len(x5t) > 0 {
+		if sum := sha1.Sum(chain[0].Raw); !bytes.Equal(sum[:], x5t) {
+			return nil, makeErrors(FieldError("x5t"), ErrX5tMismatch)
+		}
+	}
+	if x5tS256 := key.X5tS256(); len(x5tS256) > 0 {
+		if sum := sha256.Sum256(chain[0].Raw); !bytes.Equal(sum[:], x5tS256) {
+			return nil, makeErrors(FieldError("x5t#S256"), ErrX5tMismatch)
+		}
+	}
+	if option.FillX5c {
+		key.intoBaseKey().X509CertChain = chain
+	}
+	return chain, nil
+}
+
+// httpsOnlyClient copy client which reject redirect to url other than https
+func httpsOnlyClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res := *client
+	res.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "https" {
+			return makeErrors(ErrInvalidURL, fmt.Errorf("unexpected redirect scheme '%s'", req.URL.Scheme))
+		}
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		}
+		// same as default policy of http.Client
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		return nil
+	}
+	return &res
+}
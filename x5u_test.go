@@ -0,0 +1,143 @@
+package jwk_test
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/egoavara/jwk"
+)
+
+func TestResolveX5u(t *testing.T) {
+	var chainPEM []byte
+	for rest := pemECChain; ; {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			chainPEM = append(chainPEM, pem.EncodeToMemory(block)...)
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chain.pem", func(w http.ResponseWriter, r *http.Request) { w.Write(chainPEM) })
+	mux.HandleFunc("/ed.pem", func(w http.ResponseWriter, r *http.Request) { w.Write(pemCertBundle) })
+	mux.HandleFunc("/empty.pem", func(w http.ResponseWriter, r *http.Request) {})
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+	insecure := httptest.NewServer(mux)
+	defer insecure.Close()
+
+	newKey := func(t *testing.T, loc string) *jwk.ECPrivateKey {
+		k, err := jwk.ParsePEM(pemECChain)
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		eck := k.(*jwk.ECPrivateKey)
+		eck.X509CertChain = nil
+		eck.X509URL, _ = url.Parse(loc)
+		return eck
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		k := newKey(t, srv.URL+"/chain.pem")
+		chain, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(chain) != 2 || chain[0].Subject.CommonName != "jwk test leaf" {
+			t.Fatalf("expected leaf, root chain, but got %v", chain)
+		}
+		if len(k.X5c()) != 0 {
+			t.Fatalf("expected x5c not filled, but got %d", len(k.X5c()))
+		}
+	})
+	t.Run("fill x5c", func(t *testing.T) {
+		k := newKey(t, srv.URL+"/chain.pem")
+		_, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()), jwk.WithOptionFetch(func(value *jwk.OptionFetch) { value.FillX5c = true }))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+		if len(k.X5c()) != 2 {
+			t.Fatalf("expected x5c filled, but got %d", len(k.X5c()))
+		}
+		if err := jwk.VerifyX5c(k, nil); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("insecure", func(t *testing.T) {
+		k := newKey(t, insecure.URL+"/chain.pem")
+		_, err := jwk.ResolveX5u(k)
+		if !errors.Is(err, jwk.ErrInvalidURL) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrInvalidURL, err)
+		}
+		_, err = jwk.ResolveX5u(k, jwk.WithOptionFetch(func(value *jwk.OptionFetch) { value.AllowInsecureX5u = true }))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("key mismatch", func(t *testing.T) {
+		k := newKey(t, srv.URL+"/ed.pem")
+		_, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()))
+		if !errors.Is(err, jwk.ErrX5cKeyMismatch) || !errors.Is(err, jwk.FieldError("x5u")) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5cKeyMismatch, err)
+		}
+	})
+	t.Run("thumbprint mismatch", func(t *testing.T) {
+		k := newKey(t, srv.URL+"/chain.pem")
+		k.X509CertThumbprintS256 = bytes.Repeat([]byte{0x01}, 32)
+		_, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()))
+		if !errors.Is(err, jwk.ErrX5tMismatch) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5tMismatch, err)
+		}
+	})
+	t.Run("no certificate", func(t *testing.T) {
+		k := newKey(t, srv.URL+"/empty.pem")
+		_, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()))
+		if !errors.Is(err, jwk.ErrInvalidPEM) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrInvalidPEM, err)
+		}
+	})
+	t.Run("redirect to http", func(t *testing.T) {
+		redirect := httptest.NewTLSServer(http.RedirectHandler(insecure.URL+"/chain.pem", http.StatusFound))
+		defer redirect.Close()
+		k := newKey(t, redirect.URL)
+		_, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(redirect.Client()))
+		if !errors.Is(err, jwk.ErrInvalidURL) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrInvalidURL, err)
+		}
+		_, err = jwk.ResolveX5u(k, jwk.WithHTTPClient(redirect.Client()), jwk.WithOptionFetch(func(value *jwk.OptionFetch) { value.AllowInsecureX5u = true }))
+		if err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("too large", func(t *testing.T) {
+		k := newKey(t, srv.URL+"/chain.pem")
+		_, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()), jwk.WithOptionFetch(func(value *jwk.OptionFetch) { value.MaxX5uSize = int64(len(chainPEM)) - 1 }))
+		if !errors.Is(err, jwk.ErrX5uTooLarge) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrX5uTooLarge, err)
+		}
+		if _, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()), jwk.WithOptionFetch(func(value *jwk.OptionFetch) { value.MaxX5uSize = int64(len(chainPEM)) })); err != nil {
+			t.Fatalf("expected <nil>, but got %v", err)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		k := newKey(t, srv.URL+"/unknown.pem")
+		_, err := jwk.ResolveX5u(k, jwk.WithHTTPClient(srv.Client()))
+		if !errors.Is(err, jwk.ErrHTTPRequest) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrHTTPRequest, err)
+		}
+	})
+	t.Run("no x5u", func(t *testing.T) {
+		k := newKey(t, "")
+		k.X509URL = nil
+		_, err := jwk.ResolveX5u(k)
+		if !errors.Is(err, jwk.ErrNotExist) {
+			t.Fatalf("expected %v, but got %v", jwk.ErrNotExist, err)
+		}
+	})
+}